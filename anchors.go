@@ -0,0 +1,127 @@
+package gomarkdownify
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// anchorIndex records the anchor each heading will have in the converted
+// document, and where links to the original HTML ids should point.
+type anchorIndex struct {
+	// headings maps heading nodes to their anchors
+	headings map[*html.Node]headingAnchor
+	// targets maps ids from the HTML to the anchor of the heading holding them
+	targets map[string]string
+}
+
+// headingAnchor is the anchor of a single heading.
+type headingAnchor struct {
+	// id is the id found in the HTML, on the heading or an anchor inside it
+	id string
+	// anchor is the fragment that links to the heading in the output: the
+	// explicit id when HeadingIDs is set, otherwise the flavor's slug
+	anchor string
+}
+
+// collectHeadingAnchors determines the anchors of all headings in a document
+// before it is converted, so links that precede their target heading can be
+// rewritten too.
+//
+// Headings are visited in document order with the same rules convertH uses,
+// so headings in inline contexts and dropped duplicates do not take part in
+// slug numbering.
+//
+// Parameters:
+//   - doc: The root of the parsed document.
+//
+// Returns:
+//   - The anchor index for the document.
+func (c *Converter) collectHeadingAnchors(doc *html.Node) *anchorIndex {
+	index := &anchorIndex{
+		headings: make(map[*html.Node]headingAnchor),
+		targets:  make(map[string]string),
+	}
+	slugs := newSlugger(c.flavor())
+
+	c.walkHeadings(doc, func(n *html.Node, level int) {
+		if c.state.duplicateHeadings[n] && !c.options.ReportDuplicateHeadings {
+			return
+		}
+
+		// A <details> element standing for its heading holds the ids of its body
+		var ids []string
+		if n.Data != "details" {
+			ids = elementIDs(n)
+		}
+		heading := headingAnchor{anchor: slugs.slug(headingText(n))}
+		if len(ids) > 0 {
+			heading.id = ids[0]
+			if c.options.HeadingIDs {
+				heading.anchor = heading.id
+			}
+		}
+		index.headings[n] = heading
+		for _, id := range ids {
+			index.targets[id] = heading.anchor
+		}
+	})
+
+	return index
+}
+
+// elementIDs returns the id of n followed by the ids and names of the
+// anchors inside it.
+func elementIDs(n *html.Node) []string {
+	var ids []string
+	if id := getAttr(n, "id"); id != "" {
+		ids = append(ids, id)
+	}
+
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type != html.ElementNode {
+				continue
+			}
+			if child.Data == "a" {
+				for _, key := range []string{"id", "name"} {
+					if id := getAttr(child, key); id != "" && !contains(ids, id) {
+						ids = append(ids, id)
+					}
+				}
+			}
+			walk(child)
+		}
+	}
+	walk(n)
+
+	return ids
+}
+
+// headingIDMarkup returns the text of a heading decorated with its explicit
+// id: an attribute block ("Text {#id}") for flavors that support heading
+// attributes, or an inline HTML anchor otherwise.
+func (c *Converter) headingIDMarkup(text string, id string) string {
+	if supportsHeadingAttributes(c.flavor()) {
+		return text + " {#" + id + "}"
+	}
+	return `<a id="` + html.EscapeString(id) + `"></a>` + text
+}
+
+// rewriteAnchorLink points a same-document link at the anchor the converted
+// heading will have, leaving other links unchanged. Without HeadingIDs, links
+// are left alone for flavors that generate no heading ids, since no slug would
+// exist in the rendered output.
+func (c *Converter) rewriteAnchorLink(href string) string {
+	if !strings.HasPrefix(href, "#") || c.state.anchors == nil {
+		return href
+	}
+	if !c.options.HeadingIDs && !generatesHeadingIDs(c.flavor()) {
+		return href
+	}
+	if anchor, ok := c.state.anchors.targets[href[1:]]; ok {
+		return "#" + anchor
+	}
+	return href
+}
@@ -9,7 +9,8 @@ import (
 func TestStrip(t *testing.T) {
 	// Test stripping a specific tag
 	result := md("<a href=\"https://github.com/matthewwithanm\">Some Text</a>", Options{
-		Strip: []string{"a"},
+		Bullets: "*+-",
+		Strip:   []string{"a"},
 	})
 	expected := "Some Text"
 	if result != expected {
@@ -18,7 +19,8 @@ func TestStrip(t *testing.T) {
 
 	// Test with empty strip list (should convert all tags)
 	result = md("<a href=\"https://github.com/matthewwithanm\">Some Text</a>", Options{
-		Strip: []string{},
+		Bullets: "*+-",
+		Strip:   []string{},
 	})
 	expected = "[Some Text](https://github.com/matthewwithanm)"
 	if result != expected {
@@ -29,6 +31,7 @@ func TestStrip(t *testing.T) {
 func TestConvert(t *testing.T) {
 	// Test converting only a specific tag
 	result := md("<a href=\"https://github.com/matthewwithanm\">Some Text</a>", Options{
+		Bullets: "*+-",
 		Convert: []string{"a"},
 	})
 	expected := "[Some Text](https://github.com/matthewwithanm)"
@@ -38,6 +41,7 @@ func TestConvert(t *testing.T) {
 
 	// Test with empty convert list (should strip all tags)
 	result = md("<a href=\"https://github.com/matthewwithanm\">Some Text</a>", Options{
+		Bullets: "*+-",
 		Convert: []string{},
 	})
 	expected = "Some Text"
@@ -59,6 +63,7 @@ func TestStripDocument(t *testing.T) {
 
 	// Test LSTRIP
 	result, err = Convert("<p>Hello</p>", Options{
+		Bullets:       "*+-",
 		StripDocument: LSTRIP,
 	})
 	if err != nil {
@@ -71,6 +76,7 @@ func TestStripDocument(t *testing.T) {
 
 	// Test RSTRIP
 	result, err = Convert("<p>Hello</p>", Options{
+		Bullets:       "*+-",
 		StripDocument: RSTRIP,
 	})
 	if err != nil {
@@ -83,6 +89,7 @@ func TestStripDocument(t *testing.T) {
 
 	// Test STRIP
 	result, err = Convert("<p>Hello</p>", Options{
+		Bullets:       "*+-",
 		StripDocument: STRIP,
 	})
 	if err != nil {
@@ -95,6 +102,7 @@ func TestStripDocument(t *testing.T) {
 
 	// Test with empty string (no stripping)
 	result, err = Convert("<p>Hello</p>", Options{
+		Bullets:       "*+-",
 		StripDocument: "",
 	})
 	if err != nil {
@@ -116,6 +124,7 @@ func TestDefaultTitle(t *testing.T) {
 
 	// Test with DefaultTitle = true and StripLinkTitles = false
 	result = md("<a href=\"https://github.com/matthewwithanm\">Some Text</a>", Options{
+		Bullets:         "*+-",
 		DefaultTitle:    true,
 		StripLinkTitles: false,
 	})
@@ -126,6 +135,7 @@ func TestDefaultTitle(t *testing.T) {
 
 	// Test with DefaultTitle = true but title already exists and StripLinkTitles = false
 	result = md("<a href=\"https://github.com/matthewwithanm\" title=\"GitHub\">Some Text</a>", Options{
+		Bullets:         "*+-",
 		DefaultTitle:    true,
 		StripLinkTitles: false,
 	})
@@ -145,6 +155,7 @@ func TestKeepInlineImagesIn(t *testing.T) {
 
 	// Test with KeepInlineImagesIn = ["h1"]
 	result = md("<h1>Title with <img src=\"image.jpg\" alt=\"image\"></h1>", Options{
+		Bullets:            "*+-",
 		KeepInlineImagesIn: []string{"h1"},
 	})
 	expected = "\n\nTitle with ![image](image.jpg)\n=============================\n\n"
@@ -163,6 +174,7 @@ func TestNewlineStyle(t *testing.T) {
 
 	// Test with BACKSLASH
 	result = md("Line 1<br>Line 2", Options{
+		Bullets:      "*+-",
 		NewlineStyle: BACKSLASH,
 	})
 	expected = "Line 1\\\nLine 2"
@@ -181,6 +193,7 @@ func TestStrongEmSymbol(t *testing.T) {
 
 	// Test with UNDERSCORE
 	result = md("<strong>Bold</strong> and <em>Italic</em>", Options{
+		Bullets:        "*+-",
 		StrongEmSymbol: UNDERSCORE,
 	})
 	expected = "__Bold__ and _Italic_"
@@ -199,6 +212,7 @@ func TestCodeLanguage(t *testing.T) {
 
 	// Test with CodeLanguage = "go"
 	result = md("<pre><code>func main() {\n    fmt.Println(\"Hello\")\n}</code></pre>", Options{
+		Bullets:      "*+-",
 		CodeLanguage: "go",
 	})
 	expected = "\n\n```go\nfunc main() {\n    fmt.Println(\"Hello\")\n}\n```\n\n"
@@ -0,0 +1,100 @@
+package gomarkdownify
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// Doc is an HTML document to convert with ConvertBatch.
+type Doc struct {
+	// ID identifies the document in the results, such as its URL or path
+	ID string
+	// HTML is the content of the document
+	HTML string
+}
+
+// BatchResult is the outcome of converting one document with ConvertBatch.
+type BatchResult struct {
+	// ID is the ID of the converted document
+	ID string
+	// Result is the structured result of the conversion, or nil if it failed
+	Result *Result
+	// Err is the error returned by the conversion, if any
+	Err error
+}
+
+// ConvertBatch converts the documents received from inputs using a pool of
+// workers sharing the converter, and sends a BatchResult for each document
+// on the returned channel. Results are sent in the order the conversions
+// finish, which may differ from the order of the inputs.
+//
+// The returned channel is closed once inputs is closed and all documents are
+// converted, or when ctx is cancelled. Cancellation also stops the
+// conversions in progress, and documents that were not converted yet
+// produce no result.
+//
+// Parameters:
+//   - ctx: The context controlling the lifetime of the batch.
+//   - inputs: The documents to convert. The caller closes the channel when done.
+//   - workers: The number of documents converted at the same time. Values
+//     below 1 use runtime.GOMAXPROCS(0).
+//
+// Returns:
+//   - A channel receiving the result of each document.
+//
+// Example:
+//
+//	inputs := make(chan gomarkdownify.Doc)
+//	go func() {
+//	    defer close(inputs)
+//	    for url, html := range pages {
+//	        inputs <- gomarkdownify.Doc{ID: url, HTML: html}
+//	    }
+//	}()
+//	for result := range converter.ConvertBatch(ctx, inputs, 8) {
+//	    if result.Err != nil {
+//	        // handle error
+//	    }
+//	    store(result.ID, result.Result.Markdown)
+//	}
+func (c *Converter) ConvertBatch(ctx context.Context, inputs <-chan Doc, workers int) <-chan BatchResult {
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	results := make(chan BatchResult)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				var doc Doc
+				var ok bool
+				select {
+				case <-ctx.Done():
+					return
+				case doc, ok = <-inputs:
+					if !ok {
+						return
+					}
+				}
+
+				result, err := c.convertResult(ctx, doc.HTML)
+				select {
+				case <-ctx.Done():
+					return
+				case results <- BatchResult{ID: doc.ID, Result: result, Err: err}:
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	return results
+}
@@ -0,0 +1,94 @@
+package gomarkdownify
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// TestConvertBatch tests converting documents concurrently with a shared converter
+func TestConvertBatch(t *testing.T) {
+	options := DefaultOptions()
+	options.HeadingStyle = ATX
+	options.StripDocument = STRIP
+	options.TOC = TOC_TOP
+	converter := newTestConverter(t, options)
+
+	const count = 50
+	inputs := make(chan Doc)
+	go func() {
+		defer close(inputs)
+		for i := 0; i < count; i++ {
+			inputs <- Doc{
+				ID:   fmt.Sprint(i),
+				HTML: fmt.Sprintf(`<h1>Page %d</h1><h2>Intro</h2><p><a href="/%d">link</a></p>`, i, i),
+			}
+		}
+	}()
+
+	seen := make(map[string]bool)
+	for result := range converter.ConvertBatch(context.Background(), inputs, 4) {
+		if result.Err != nil {
+			t.Fatalf("%s: %v", result.ID, result.Err)
+		}
+		expected := fmt.Sprintf("* [Page %s](#page-%s)\n  + [Intro](#intro)\n\n# Page %s\n\n## Intro\n\n[link](/%s)", result.ID, result.ID, result.ID, result.ID)
+		if result.Result.Markdown != expected {
+			t.Errorf("%s: Expected %q, got %q", result.ID, expected, result.Result.Markdown)
+		}
+		if len(result.Result.Links) != 1 || result.Result.Links[0].URL != "/"+result.ID {
+			t.Errorf("%s: Expected the document's own link, got %+v", result.ID, result.Result.Links)
+		}
+		seen[result.ID] = true
+	}
+	if len(seen) != count {
+		t.Errorf("Expected %d results, got %d", count, len(seen))
+	}
+}
+
+// TestConvertBatchCancel tests that cancelling the context closes the results
+func TestConvertBatchCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	inputs := make(chan Doc)
+	results := newTestConverter(t, DefaultOptions()).ConvertBatch(ctx, inputs, 2)
+
+	inputs <- Doc{ID: "first", HTML: "<p>first</p>"}
+	if result := <-results; result.ID != "first" || result.Err != nil {
+		t.Errorf("Expected the first result, got %+v", result)
+	}
+
+	// The input channel stays open, cancellation alone ends the batch
+	cancel()
+	for result := range results {
+		t.Errorf("Expected no results after cancellation, got %+v", result)
+	}
+}
+
+// TestConverterConcurrentUse tests sharing a converter between goroutines
+func TestConverterConcurrentUse(t *testing.T) {
+	options := DefaultOptions()
+	options.Footnotes = true
+	converter := newTestConverter(t, options)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			html := fmt.Sprintf(`<h2>Same</h2><h2>Same</h2><p>Note<sup><a href="#fn%d">1</a></sup></p><ol class="footnotes"><li id="fn%d">Text %d</li></ol>`, i, i, i)
+			result, err := converter.ConvertResult(html)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			expected := fmt.Sprintf("Same\n----\n\nNote[^1]\n\n[^1]: Text %d\n", i)
+			if result.Markdown != expected {
+				t.Errorf("Expected %q, got %q", expected, result.Markdown)
+			}
+			if len(result.Headings) != 1 || len(result.Warnings) != 1 {
+				t.Errorf("Expected one heading and one warning, got %+v and %q", result.Headings, result.Warnings)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,178 @@
+package gomarkdownify
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// BenchmarkConvertPages measures converting the realistic pages in
+// testdata/pages, with the default options and with the optional
+// conversions enabled.
+func BenchmarkConvertPages(b *testing.B) {
+	pages, err := filepath.Glob(filepath.Join("testdata", "pages", "*.html"))
+	if err != nil || len(pages) == 0 {
+		b.Fatalf("No pages found: %v", err)
+	}
+
+	rich := DefaultOptions()
+	rich.HeadingStyle = ATX
+	rich.Footnotes = true
+	rich.Math = true
+	rich.Callouts = true
+	rich.HeadingIDs = true
+	rich.TOC = TOC_TOP
+	rich.FrontMatter = YAML
+
+	for _, page := range pages {
+		data, err := os.ReadFile(page)
+		if err != nil {
+			b.Fatal(err)
+		}
+		input := string(data)
+		name := strings.TrimSuffix(filepath.Base(page), ".html")
+
+		for _, config := range []struct {
+			name    string
+			options Options
+		}{
+			{"default", DefaultOptions()},
+			{"rich", rich},
+		} {
+			b.Run(name+"/"+config.name, func(b *testing.B) {
+				converter := newTestConverter(b, config.options)
+				b.ReportAllocs()
+				b.SetBytes(int64(len(input)))
+				for i := 0; i < b.N; i++ {
+					if _, err := converter.Convert(input); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
+
+// BenchmarkConvertResult measures collecting the structured result of a page.
+func BenchmarkConvertResult(b *testing.B) {
+	data, err := os.ReadFile(filepath.Join("testdata", "pages", "blog.html"))
+	if err != nil {
+		b.Fatal(err)
+	}
+	input := string(data)
+	converter := newTestConverter(b, DefaultOptions())
+	b.ReportAllocs()
+	b.SetBytes(int64(len(input)))
+	for i := 0; i < b.N; i++ {
+		if _, err := converter.ConvertResult(input); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// deepDocument returns a document with depth levels of nested lists, each
+// item holding a paragraph with inline formatting
+func deepDocument(depth int) string {
+	var sb strings.Builder
+	for i := 0; i < depth; i++ {
+		fmt.Fprintf(&sb, "<ul><li><p>Item <b>%d</b> with <a href=\"/%d\">a link</a></p>", i, i)
+	}
+	for i := 0; i < depth; i++ {
+		sb.WriteString("</li></ul>")
+	}
+	return sb.String()
+}
+
+// nestedDocument returns a document with depth nested <div> elements, each
+// holding a sentence with inline formatting
+func nestedDocument(depth int) string {
+	var sb strings.Builder
+	for i := 0; i < depth; i++ {
+		fmt.Fprintf(&sb, "<div>Level <b>%d</b> ", i)
+	}
+	for i := 0; i < depth; i++ {
+		sb.WriteString("</div>")
+	}
+	return sb.String()
+}
+
+// wideDocument returns a document with an ordered list of count items
+func wideDocument(count int) string {
+	var sb strings.Builder
+	sb.WriteString("<ol>")
+	for i := 0; i < count; i++ {
+		fmt.Fprintf(&sb, "<li>Item <em>%d</em></li>", i)
+	}
+	sb.WriteString("</ol>")
+	return sb.String()
+}
+
+// BenchmarkConvertDeep measures converting deeply nested lists. The output
+// grows with the square of the depth, as every level is indented, but the
+// allocations per level must not grow with the depth.
+func BenchmarkConvertDeep(b *testing.B) {
+	allocsPerLevel := func(depth int) float64 {
+		converter := newTestConverter(b, DefaultOptions())
+		input := deepDocument(depth)
+		return testing.AllocsPerRun(1, func() {
+			if _, err := converter.Convert(input); err != nil {
+				b.Fatal(err)
+			}
+		}) / float64(depth)
+	}
+	if shallow, deep := allocsPerLevel(10), allocsPerLevel(500); deep > 1.5*shallow {
+		b.Errorf("Allocations per level grow with the depth: %.1f at depth 10, %.1f at depth 500", shallow, deep)
+	}
+
+	for _, depth := range []int{10, 100, 500} {
+		input := deepDocument(depth)
+		b.Run(fmt.Sprint(depth), func(b *testing.B) {
+			converter := newTestConverter(b, DefaultOptions())
+			b.ReportAllocs()
+			b.SetBytes(int64(len(input)))
+			for i := 0; i < b.N; i++ {
+				if _, err := converter.Convert(input); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkConvertNested measures converting deeply nested blocks, where
+// tracking the ancestors used to copy them for every element.
+func BenchmarkConvertNested(b *testing.B) {
+	for _, depth := range []int{10, 100, 1000} {
+		input := nestedDocument(depth)
+		b.Run(fmt.Sprint(depth), func(b *testing.B) {
+			converter := newTestConverter(b, DefaultOptions())
+			b.ReportAllocs()
+			b.SetBytes(int64(len(input)))
+			for i := 0; i < b.N; i++ {
+				if _, err := converter.Convert(input); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkConvertWide measures converting long lists, where numbering
+// items used to scan all previous items.
+func BenchmarkConvertWide(b *testing.B) {
+	for _, count := range []int{100, 1000, 5000} {
+		input := wideDocument(count)
+		b.Run(fmt.Sprint(count), func(b *testing.B) {
+			converter := newTestConverter(b, DefaultOptions())
+			b.ReportAllocs()
+			b.SetBytes(int64(len(input)))
+			for i := 0; i < b.N; i++ {
+				if _, err := converter.Convert(input); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -97,3 +97,73 @@ func TestBlockquoteAdvanced(t *testing.T) {
 		t.Errorf("Expected blockquote with inline formatting, got %q", result)
 	}
 }
+
+// TestBlockquoteBlocks tests blockquotes containing block elements
+func TestBlockquoteBlocks(t *testing.T) {
+	tests := []struct {
+		name     string
+		html     string
+		expected string
+	}{
+		{
+			name:     "Paragraphs",
+			html:     "<blockquote><p>First</p><p>Second</p></blockquote>",
+			expected: "> First\n>\n> Second",
+		},
+		{
+			name:     "Line break",
+			html:     "<blockquote>First<br>Second</blockquote>",
+			expected: "> First  \n> Second",
+		},
+		{
+			name:     "List and code block",
+			html:     "<blockquote><ul><li>one</li><li>two<ul><li>sub</li></ul></li></ul><pre><code>x\n\n  y</code></pre></blockquote>",
+			expected: "> * one\n> * two\n>   + sub\n>\n> ```\n> x\n>\n>   y\n> ```",
+		},
+		{
+			name:     "Code block with blank lines",
+			html:     "<blockquote><p>Code:</p><pre><code>a\n\n\n    \nb</code></pre></blockquote>",
+			expected: "> Code:\n>\n> ```\n> a\n>\n>\n>     \n> b\n> ```",
+		},
+		{
+			name:     "Heading",
+			html:     "<blockquote><h2>Title</h2><p>Text</p></blockquote>",
+			expected: "> ## Title\n>\n> Text",
+		},
+		{
+			name:     "Nested blockquotes",
+			html:     "<blockquote><p>a</p><blockquote><p>b</p><p>c</p></blockquote><p>d</p></blockquote>",
+			expected: "> a\n>\n> > b\n> >\n> > c\n>\n> d",
+		},
+		{
+			name:     "Three levels",
+			html:     "<blockquote><blockquote><blockquote><p>deep</p><p>deeper</p></blockquote></blockquote></blockquote>",
+			expected: "> > > deep\n> > >\n> > > deeper",
+		},
+		{
+			name:     "Blockquote in a list",
+			html:     "<ul><li>item<blockquote><p>q1</p><p>q2</p></blockquote></li><li>next</li></ul>",
+			expected: "* item\n  > q1\n  >\n  > q2\n* next",
+		},
+		{
+			name:     "Between paragraphs",
+			html:     "<p>before</p><blockquote><p>quote</p></blockquote><p>after</p>",
+			expected: "before\n\n> quote\n\nafter",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := DefaultOptions()
+			options.HeadingStyle = ATX
+			options.StripDocument = STRIP
+			result, err := Convert(tt.html, options)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,335 @@
+package gomarkdownify
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+)
+
+// CalloutRule describes how to recognize callouts (also called admonitions
+// or alerts) in HTML. An element is a callout when it matches Tag and has
+// the class Class.
+type CalloutRule struct {
+	// Tag is the element name to match, or "" to match any element
+	Tag string `yaml:"Tag"`
+	// Class is a class the element must have, or "" to match by Tag only
+	Class string `yaml:"Class"`
+	// TypePrefix is the prefix of the class that names the callout type, such as
+	// "alert-" for <div class="alert alert-warning">. When empty, any class that
+	// is a known callout type (note, tip, warning, danger, ...) names the type.
+	TypePrefix string `yaml:"TypePrefix"`
+	// Type is the callout type used when no class or role names one. Defaults
+	// to "note" for rules with a Class; a rule matching by Tag only needs the
+	// element to name its type, so that plain elements are left alone.
+	Type string `yaml:"Type"`
+}
+
+// DefaultCalloutRules returns the rules used when Options.CalloutRules is
+// nil. They recognize the admonitions of MkDocs, Sphinx and Docusaurus,
+// Bootstrap alerts, Confluence info macros, GitHub alerts and <aside>
+// elements with a class or role naming a callout type, such as
+// <aside class="warning"> or <aside role="note">.
+func DefaultCalloutRules() []CalloutRule {
+	return []CalloutRule{
+		{Class: "theme-admonition", TypePrefix: "theme-admonition-"},
+		{Class: "admonition"},
+		{Class: "markdown-alert", TypePrefix: "markdown-alert-"},
+		{Class: "confluence-information-macro", TypePrefix: "confluence-information-macro-"},
+		{Class: "alert", TypePrefix: "alert-"},
+		{Tag: "aside"},
+	}
+}
+
+// calloutTypes maps the type names found in class attributes to the callout
+// types used in the output.
+var calloutTypes = map[string]string{
+	"note":        "note",
+	"seealso":     "note",
+	"primary":     "note",
+	"secondary":   "note",
+	"abstract":    "abstract",
+	"summary":     "abstract",
+	"tldr":        "abstract",
+	"info":        "info",
+	"information": "info",
+	"todo":        "info",
+	"tip":         "tip",
+	"hint":        "tip",
+	"success":     "success",
+	"check":       "success",
+	"done":        "success",
+	"question":    "question",
+	"help":        "question",
+	"faq":         "question",
+	"important":   "important",
+	"warning":     "warning",
+	"attention":   "warning",
+	"caution":     "caution",
+	"failure":     "failure",
+	"fail":        "failure",
+	"missing":     "failure",
+	"danger":      "danger",
+	"error":       "danger",
+	"bug":         "bug",
+	"example":     "example",
+	"quote":       "quote",
+	"cite":        "quote",
+}
+
+// githubAlertTypes maps callout types to the five GitHub alert types.
+var githubAlertTypes = map[string]string{
+	"tip":       "TIP",
+	"success":   "TIP",
+	"important": "IMPORTANT",
+	"warning":   "WARNING",
+	"caution":   "CAUTION",
+	"failure":   "CAUTION",
+	"danger":    "CAUTION",
+	"bug":       "CAUTION",
+}
+
+// calloutIndex records the callouts found in a document before conversion.
+type calloutIndex struct {
+	// callouts maps callout elements to their type and title
+	callouts map[*html.Node]callout
+	// skip holds the title elements, which are not converted in place
+	skip map[*html.Node]bool
+}
+
+// callout is a single recognized callout.
+type callout struct {
+	// kind is the callout type, such as "warning"
+	kind string
+	// title is the plain text of the title element, if any
+	title string
+	// collapsible is set for callouts made from <details> elements, which
+	// are collapsed unless open is set
+	collapsible bool
+	open        bool
+}
+
+// findCallouts scans a parsed document for elements matching the callout rules.
+//
+// The type of a callout comes from its classes as described by the matching
+// rule, or from its role. The title is the first element inside the callout
+// with a title class (see isCalloutTitleClass), which is removed from the body.
+//
+// Parameters:
+//   - doc: The root of the parsed document.
+//
+// Returns:
+//   - The callout index for the document.
+func (c *Converter) findCallouts(doc *html.Node) *calloutIndex {
+	rules := c.options.CalloutRules
+	if rules == nil {
+		rules = DefaultCalloutRules()
+	}
+	index := &calloutIndex{
+		callouts: make(map[*html.Node]callout),
+		skip:     make(map[*html.Node]bool),
+	}
+
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			if kind, ok := matchCallout(n, rules); ok {
+				found := callout{kind: kind}
+				if title := findCalloutTitle(n, rules); title != nil {
+					index.skip[title] = true
+					found.title = strings.TrimSpace(reAllWhitespace.ReplaceAllString(textContent(title), " "))
+				}
+				index.callouts[n] = found
+			}
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(doc)
+
+	return index
+}
+
+// matchCallout returns the callout type of n if it matches one of the rules.
+func matchCallout(n *html.Node, rules []CalloutRule) (string, bool) {
+	for _, rule := range rules {
+		if rule.Tag == "" && rule.Class == "" {
+			continue
+		}
+		if rule.Tag != "" && n.Data != rule.Tag {
+			continue
+		}
+		if rule.Class != "" && !hasClass(n, rule.Class) {
+			continue
+		}
+
+		for _, class := range strings.Fields(getAttr(n, "class")) {
+			class = strings.ToLower(class)
+			if rule.TypePrefix != "" {
+				if !strings.HasPrefix(class, rule.TypePrefix) {
+					continue
+				}
+				class = strings.TrimLeft(class[len(rule.TypePrefix):], "-")
+			}
+			if kind, ok := calloutTypes[class]; ok {
+				return kind, true
+			}
+		}
+
+		if kind, ok := calloutTypes[strings.ToLower(getAttr(n, "role"))]; ok {
+			return kind, true
+		}
+
+		if kind, ok := calloutTypes[strings.ToLower(rule.Type)]; ok {
+			return kind, true
+		}
+		if rule.Type != "" {
+			return strings.ToLower(rule.Type), true
+		}
+		if rule.Class != "" {
+			return "note", true
+		}
+	}
+	return "", false
+}
+
+// findCalloutTitle returns the title element of a callout, or nil if it has
+// none. Nested callouts are not searched.
+func findCalloutTitle(n *html.Node, rules []CalloutRule) *html.Node {
+	// Title classes are named after the classes of the callout
+	var prefixes []string
+	for _, class := range strings.Fields(strings.ToLower(getAttr(n, "class"))) {
+		prefixes = append(prefixes, class)
+		if i := strings.LastIndex(class, "-"); i >= 0 && i < len(class)-1 {
+			prefixes = append(prefixes, class[i+1:])
+		}
+	}
+
+	var find func(n *html.Node) *html.Node
+	find = func(n *html.Node) *html.Node {
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type != html.ElementNode {
+				continue
+			}
+			if _, ok := matchCallout(child, rules); ok {
+				continue
+			}
+			for _, class := range strings.Fields(strings.ToLower(getAttr(child, "class"))) {
+				if isCalloutTitleClass(class, prefixes) {
+					return child
+				}
+			}
+			if title := find(child); title != nil {
+				return title
+			}
+		}
+		return nil
+	}
+	return find(n)
+}
+
+// isCalloutTitleClass reports whether a lowercase class token names the title
+// of a callout: "title" or "heading", optionally after one of the prefixes
+// taken from the callout classes ("admonition-title" in an "admonition",
+// "alert-heading" in an "alert"). A CSS module hash such as the "_x" of
+// "admonitionHeading_x" is ignored. Classes that merely contain the words,
+// such as "subtitle" or "no-heading", are not titles.
+func isCalloutTitleClass(class string, prefixes []string) bool {
+	if i := strings.LastIndex(class, "_"); i > 0 {
+		if isCalloutTitleClass(class[:i], prefixes) {
+			return true
+		}
+	}
+	for _, word := range []string{"title", "heading"} {
+		if class == word {
+			return true
+		}
+		for _, prefix := range prefixes {
+			if class == prefix+"-"+word || class == prefix+word {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// convertCallout converts a callout to the syntax of the chosen flavor:
+// GitHub alerts ("> [!WARNING]") for GFM, callouts ("> [!warning] Title") for
+// OBSIDIAN, admonitions ("!!! warning "Title"") for MKDOCS, and a blockquote
+// starting with the title in strong emphasis otherwise.
+//
+// GitHub alerts have no titles, so a title other than the default one is
+// kept as the first line of the alert. Collapsible callouts use "???" in
+// MkDocs and a "-" or "+" fold marker in Obsidian; other flavors cannot
+// collapse callouts.
+//
+// Parameters:
+//   - co: The callout type and title.
+//   - text: The converted content of the callout.
+//   - parents: The context of the ancestors, used for context-aware conversion.
+//
+// Returns:
+//   - The Markdown representation of the callout.
+func (c *Converter) convertCallout(co callout, text string, parents tagContext) string {
+	text = strings.TrimSpace(text)
+
+	if parents.has(inInline) {
+		return " " + strings.TrimSpace(co.title+" "+text) + " "
+	}
+
+	// A title that only repeats the type is the default title
+	title := co.title
+	if strings.EqualFold(title, co.kind) {
+		title = ""
+	}
+	title = c.escape(title, parents)
+	strong := strings.Repeat(c.strongEmSymbol(), 2)
+
+	var header []string
+	switch c.flavor() {
+	case MKDOCS:
+		marker := "!!! " + co.kind
+		if co.collapsible && co.open {
+			marker = "???+ " + co.kind
+		} else if co.collapsible {
+			marker = "??? " + co.kind
+		}
+		if title != "" {
+			marker += ` "` + strings.ReplaceAll(title, `"`, `\"`) + `"`
+		}
+		if text == "" {
+			return "\n\n" + marker + "\n\n"
+		}
+		return "\n\n" + marker + "\n\n    " + indentLines(reMultipleNewlines.ReplaceAllString(text, "\n\n"), "    ") + "\n\n"
+	case OBSIDIAN:
+		fold := ""
+		if co.collapsible && co.open {
+			fold = "+"
+		} else if co.collapsible {
+			fold = "-"
+		}
+		header = append(header, strings.TrimSpace("[!"+co.kind+"]"+fold+" "+title))
+	case GFM:
+		kind, ok := githubAlertTypes[co.kind]
+		if !ok {
+			kind = "NOTE"
+		}
+		header = append(header, "[!"+kind+"]")
+		if title != "" {
+			header = append(header, strong+title+strong, "")
+		}
+	default:
+		if title == "" {
+			r, size := utf8.DecodeRuneInString(co.kind)
+			title = string(unicode.ToUpper(r)) + co.kind[size:]
+		}
+		header = append(header, strong+title+strong, "")
+	}
+
+	if text != "" {
+		header = append(header, text)
+	}
+	return "\n\n" + quoteLines(strings.Join(header, "\n")) + "\n\n"
+}
@@ -0,0 +1,156 @@
+package gomarkdownify
+
+import (
+	"testing"
+)
+
+// TestCallouts tests converting callouts to the syntax of each flavor
+func TestCallouts(t *testing.T) {
+	mkdocs := `<div class="admonition warning"><p class="admonition-title">Heads up</p><p>First</p><p>Second</p></div>`
+
+	tests := []struct {
+		name     string
+		html     string
+		flavor   string
+		expected string
+	}{
+		{
+			name:     "GitHub alert with title",
+			html:     mkdocs,
+			flavor:   GFM,
+			expected: "> [!WARNING]\n> **Heads up**\n>\n> First\n>\n> Second",
+		},
+		{
+			name:     "Obsidian callout",
+			html:     mkdocs,
+			flavor:   OBSIDIAN,
+			expected: "> [!warning] Heads up\n> First\n>\n> Second",
+		},
+		{
+			name:     "MkDocs admonition",
+			html:     mkdocs,
+			flavor:   MKDOCS,
+			expected: "!!! warning \"Heads up\"\n\n    First\n\n    Second",
+		},
+		{
+			name:     "Blockquote fallback",
+			html:     mkdocs,
+			flavor:   COMMONMARK,
+			expected: "> **Heads up**\n>\n> First\n>\n> Second",
+		},
+		{
+			name:     "Default title is dropped",
+			html:     `<div class="admonition note"><p class="admonition-title">Note</p><p>Text</p></div>`,
+			flavor:   MKDOCS,
+			expected: "!!! note\n\n    Text",
+		},
+		{
+			name:     "Fallback uses the type as title",
+			html:     `<aside class="tip"><p>Text</p></aside>`,
+			flavor:   PANDOC,
+			expected: "> **Tip**\n>\n> Text",
+		},
+		{
+			name:     "Bootstrap alert",
+			html:     `<div class="alert alert-danger alert-dismissible" role="alert">Do not <b>do</b> this</div>`,
+			flavor:   GFM,
+			expected: "> [!CAUTION]\n> Do not **do** this",
+		},
+		{
+			name: "Docusaurus admonition",
+			html: `<div class="theme-admonition theme-admonition-info alert alert--info">` +
+				`<div class="admonitionHeading_x"><span class="icon"></span>info</div>` +
+				`<div class="admonitionContent_y"><p>Text</p></div></div>`,
+			flavor:   OBSIDIAN,
+			expected: "> [!info]\n> Text",
+		},
+		{
+			name:     "Confluence macro",
+			html:     `<div class="confluence-information-macro confluence-information-macro-information"><div class="confluence-information-macro-body"><p>Text</p></div></div>`,
+			flavor:   GFM,
+			expected: "> [!NOTE]\n> Text",
+		},
+		{
+			name:     "Aside without type",
+			html:     `<aside><p>Text</p></aside>`,
+			flavor:   GFM,
+			expected: "Text",
+		},
+		{
+			name:     "Aside with a role",
+			html:     `<aside role="note"><p>Text</p></aside>`,
+			flavor:   GFM,
+			expected: "> [!NOTE]\n> Text",
+		},
+		{
+			name:     "Title classes are whole tokens",
+			html:     `<div class="admonition tip"><p class="subtitle">Sub</p><p class="no-heading">Text</p></div>`,
+			flavor:   OBSIDIAN,
+			expected: "> [!tip]\n> Sub\n>\n> Text",
+		},
+		{
+			name:     "Title class of the callout",
+			html:     `<div class="alert alert-success"><h4 class="alert-heading">Done</h4><p>Text</p></div>`,
+			flavor:   OBSIDIAN,
+			expected: "> [!success] Done\n> Text",
+		},
+		{
+			name:     "Nested in a list",
+			html:     `<ul><li>Item<div class="admonition tip"><p>Text</p></div></li></ul>`,
+			flavor:   GFM,
+			expected: "* Item\n\n  > [!TIP]\n  > Text",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := DefaultOptions()
+			options.StripDocument = STRIP
+			options.Callouts = true
+			options.Flavor = tt.flavor
+			result, err := Convert(tt.html, options)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+// TestCalloutRules tests custom callout recognition rules
+func TestCalloutRules(t *testing.T) {
+	input := `<div class="box box-warning"><p>Text</p></div><div class="admonition warning"><p>Plain</p></div>`
+
+	options := DefaultOptions()
+	options.StripDocument = STRIP
+	options.Callouts = true
+	options.CalloutRules = []CalloutRule{
+		{Class: "box", TypePrefix: "box-"},
+		{Tag: "section", Class: "hint", Type: "Tip"},
+	}
+	result, err := Convert(input+`<section class="hint">Typed</section>`, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "> [!WARNING]\n> Text\n\nPlain\n\n> [!TIP]\n> Typed"
+	if result != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+
+	// Type names are capitalized by rune in the fallback title
+	options.Flavor = COMMONMARK
+	options.CalloutRules = []CalloutRule{{Class: "box", Type: "ämne"}}
+	if result, _ := Convert(`<div class="box">Text</div>`, options); result != "> **Ämne**\n>\n> Text" {
+		t.Errorf("Expected %q, got %q", "> **Ämne**\n>\n> Text", result)
+	}
+	options.Flavor = ""
+	options.CalloutRules = nil
+
+	// Callouts are left alone unless enabled
+	options.Callouts = false
+	if result, _ := Convert(input, options); result != "Text\n\nPlain" {
+		t.Errorf("Expected %q, got %q", "Text\n\nPlain", result)
+	}
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+
+	gomarkdownify "github.com/mrjoshuak/go-markdownify"
+	"gopkg.in/yaml.v3"
+)
+
+// optionFlags binds a command-line flag to every Options field that can be
+// expressed as text. Callback fields and callout rules can only be set in
+// a config file, or not at all.
+type optionFlags struct {
+	// values holds the flag values, starting from the default options
+	values gomarkdownify.Options
+	// fields maps flag names to Options field indexes
+	fields map[string]int
+}
+
+// registerOptionFlags defines a flag for each supported Options field on fs.
+// Flag names are the field names in kebab case, so HeadingStyle becomes
+// -heading-style and TOCMinLevel becomes -toc-min-level.
+func registerOptionFlags(fs *flag.FlagSet) *optionFlags {
+	flags := &optionFlags{
+		values: gomarkdownify.DefaultOptions(),
+		fields: make(map[string]int),
+	}
+
+	values := reflect.ValueOf(&flags.values).Elem()
+	for i := 0; i < values.NumField(); i++ {
+		field := values.Type().Field(i)
+		value := &fieldValue{value: values.Field(i)}
+		if !value.supported() {
+			continue
+		}
+		name := kebabCase(field.Name)
+		flags.fields[name] = i
+		fs.Var(value, name, "set Options."+field.Name+value.hint())
+	}
+
+	return flags
+}
+
+// apply copies the values of the flags that were set on the command line
+// to options, so they take precedence over the config file.
+func (f *optionFlags) apply(fs *flag.FlagSet, options *gomarkdownify.Options) {
+	target := reflect.ValueOf(options).Elem()
+	source := reflect.ValueOf(&f.values).Elem()
+	fs.Visit(func(fl *flag.Flag) {
+		if i, ok := f.fields[fl.Name]; ok {
+			target.Field(i).Set(source.Field(i))
+		}
+	})
+}
+
+// loadConfig reads options from a config file over the given options.
+// Files ending in .yaml or .yml are YAML, with keys matching Options field
+// names exactly; other files are JSON, with keys matched case-insensitively.
+// Unknown keys are errors, so typos are not silently ignored.
+func loadConfig(path string, options *gomarkdownify.Options) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		err = decoder.Decode(options)
+		if errors.Is(err, io.EOF) {
+			// An empty file keeps the options
+			err = nil
+		}
+	default:
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(options)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// fieldValue is a flag.Value that sets an Options field.
+type fieldValue struct {
+	value reflect.Value
+}
+
+// supported reports whether the field can be set from a flag.
+func (v *fieldValue) supported() bool {
+	switch v.value.Kind() {
+	case reflect.Bool, reflect.Int, reflect.String:
+		return true
+	case reflect.Slice, reflect.Array:
+		return v.value.Type().Elem().Kind() == reflect.String
+	}
+	return false
+}
+
+// hint describes the expected format of list values.
+func (v *fieldValue) hint() string {
+	switch v.value.Kind() {
+	case reflect.Slice:
+		return " (comma separated)"
+	case reflect.Array:
+		return fmt.Sprintf(" (%d comma separated values)", v.value.Len())
+	}
+	return ""
+}
+
+// String returns the current value of the field.
+func (v *fieldValue) String() string {
+	// Zero values are not shown as defaults in the usage message
+	if !v.value.IsValid() || v.value.IsZero() {
+		return ""
+	}
+	switch v.value.Kind() {
+	case reflect.Slice, reflect.Array:
+		items := make([]string, v.value.Len())
+		for i := range items {
+			items[i] = v.value.Index(i).String()
+		}
+		return strings.Join(items, ",")
+	}
+	return fmt.Sprint(v.value.Interface())
+}
+
+// Set parses a flag argument into the field.
+func (v *fieldValue) Set(s string) error {
+	switch v.value.Kind() {
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.value.SetBool(b)
+	case reflect.Int:
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return err
+		}
+		v.value.SetInt(int64(n))
+	case reflect.String:
+		v.value.SetString(s)
+	case reflect.Slice:
+		var items []string
+		if s != "" {
+			items = strings.Split(s, ",")
+		}
+		v.value.Set(reflect.ValueOf(items))
+	case reflect.Array:
+		items := strings.Split(s, ",")
+		if len(items) != v.value.Len() {
+			return fmt.Errorf("expected %d comma separated values", v.value.Len())
+		}
+		for i, item := range items {
+			v.value.Index(i).SetString(item)
+		}
+	}
+	return nil
+}
+
+// IsBoolFlag allows boolean flags to be given without a value.
+func (v *fieldValue) IsBoolFlag() bool {
+	return v.value.Kind() == reflect.Bool
+}
+
+// kebabCase converts a Go field name to a flag name: "HeadingStyle" becomes
+// "heading-style", "TOCMinLevel" becomes "toc-min-level" and "HeadingIDs"
+// becomes "heading-ids".
+func kebabCase(name string) string {
+	runes := []rune(name)
+	var out strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			// Break before an upper case letter that starts a word, either after
+			// a lower case letter or at the end of an acronym ("TOCMin"), but
+			// not before a plural "s" ("IDs")
+			endsAcronym := unicode.IsUpper(prev) && i+1 < len(runes) && unicode.IsLower(runes[i+1]) &&
+				!(runes[i+1] == 's' && (i+2 == len(runes) || unicode.IsUpper(runes[i+2])))
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || endsAcronym {
+				out.WriteRune('-')
+			}
+		}
+		out.WriteRune(unicode.ToLower(r))
+	}
+	return out.String()
+}
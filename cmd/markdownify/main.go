@@ -0,0 +1,272 @@
+// Command markdownify converts HTML to Markdown.
+//
+// It reads HTML from standard input, files, glob patterns or directories
+// and writes Markdown to standard output or to mirrored paths in an output
+// directory. With -site, directories are converted as static sites: links
+// between pages are rewritten to the Markdown files and images are copied.
+// Every field of gomarkdownify.Options that can be written as text is
+// available as a flag. Options start from the defaults, or from the preset
+// of a Markdown flavor with -preset, and can be loaded from a JSON or YAML
+// config file; flags given on the command line take precedence.
+//
+// Usage:
+//
+//	markdownify [flags] [file|glob|directory ...]
+//
+// Examples:
+//
+//	curl -s https://example.com | markdownify -heading-style atx
+//	markdownify -o README.md index.html
+//	markdownify -out-dir docs 'site/*.html'
+//	markdownify -config markdownify.yaml -out-dir docs site
+//	markdownify -preset gfm index.html
+//	markdownify -site -out-dir docs public
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	gomarkdownify "github.com/mrjoshuak/go-markdownify"
+)
+
+// input is an HTML file to convert.
+type input struct {
+	// path is the file to read, or "-" for standard input
+	path string
+	// rel is the path of the output file relative to the output directory,
+	// without extension
+	rel string
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes the command with the given arguments and streams, and
+// returns the exit code.
+func run(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("markdownify", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	configPath := flags.String("config", "", "load options from a JSON or YAML (.yaml, .yml) `file`")
+	preset := flags.String("preset", "", "start from the options preset of a Markdown `flavor` instead of the defaults")
+	outputPath := flags.String("o", "", "write the Markdown to `file` instead of standard output (single input only)")
+	outputDir := flags.String("out-dir", "", "write each input to a .md file with the same relative path in `directory`")
+	extension := flags.String("ext", ".md", "file `extension` of the files written to -out-dir")
+	site := flags.Bool("site", false, "convert site directories to -out-dir, rewriting links between pages and copying images")
+	optionFlags := registerOptionFlags(flags)
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: markdownify [flags] [file|glob|directory ...]\n\n")
+		fmt.Fprintf(stderr, "Converts HTML to Markdown. Reads standard input when no files are given.\n\nFlags:\n")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+
+	options := gomarkdownify.DefaultOptions()
+	if *preset != "" {
+		var err error
+		if options, err = gomarkdownify.Preset(*preset); err != nil {
+			fmt.Fprintln(stderr, err)
+			return 2
+		}
+	}
+	if *configPath != "" {
+		if err := loadConfig(*configPath, &options); err != nil {
+			fmt.Fprintf(stderr, "markdownify: %v\n", err)
+			return 1
+		}
+	}
+	optionFlags.apply(flags, &options)
+
+	converter, err := gomarkdownify.NewConverter(options)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+	if *site {
+		return runSite(converter, flags.Args(), *outputDir, stderr)
+	}
+
+	inputs, err := expandInputs(flags.Args())
+	if err != nil {
+		fmt.Fprintf(stderr, "markdownify: %v\n", err)
+		return 1
+	}
+	if *outputPath != "" && (len(inputs) != 1 || *outputDir != "") {
+		fmt.Fprintf(stderr, "markdownify: -o needs a single input and cannot be combined with -out-dir\n")
+		return 2
+	}
+
+	status := 0
+	for i, in := range inputs {
+		markdown, err := convertInput(converter, in, stdin)
+		if err != nil {
+			fmt.Fprintf(stderr, "markdownify: %v\n", err)
+			status = 1
+			continue
+		}
+
+		switch {
+		case *outputPath != "":
+			err = os.WriteFile(*outputPath, []byte(markdown), 0o644)
+		case *outputDir != "":
+			err = writeMirrored(*outputDir, in.rel+*extension, markdown)
+		default:
+			if i > 0 {
+				markdown = "\n" + markdown
+			}
+			_, err = io.WriteString(stdout, markdown)
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "markdownify: %v\n", err)
+			status = 1
+		}
+	}
+
+	return status
+}
+
+// runSite converts each site directory to the output directory and reports
+// broken internal links and the pages that failed. Broken links are warnings
+// and do not fail the command.
+func runSite(converter *gomarkdownify.Converter, dirs []string, outputDir string, stderr io.Writer) int {
+	if outputDir == "" || len(dirs) == 0 {
+		fmt.Fprintf(stderr, "markdownify: -site needs -out-dir and at least one directory\n")
+		return 2
+	}
+
+	status := 0
+	for _, dir := range dirs {
+		report, err := converter.ConvertSite(dir, outputDir)
+		if err != nil {
+			fmt.Fprintf(stderr, "markdownify: %v\n", err)
+			status = 1
+			continue
+		}
+		for _, link := range report.BrokenLinks {
+			fmt.Fprintf(stderr, "markdownify: %s: broken link %s\n", filepath.Join(dir, link.Page), link.URL)
+		}
+		for _, failed := range report.Failed {
+			fmt.Fprintf(stderr, "markdownify: %s: %v\n", filepath.Join(dir, failed.Page), failed.Err)
+			status = 1
+		}
+	}
+
+	return status
+}
+
+// expandInputs resolves the command-line arguments to the files to convert.
+// Glob patterns are expanded, directories are searched recursively for .html
+// and .htm files, and no arguments or "-" mean standard input.
+func expandInputs(args []string) ([]input, error) {
+	if len(args) == 0 {
+		return []input{{path: "-", rel: "stdin"}}, nil
+	}
+
+	var inputs []input
+	for _, arg := range args {
+		if arg == "-" {
+			inputs = append(inputs, input{path: "-", rel: "stdin"})
+			continue
+		}
+
+		matches, err := filepath.Glob(arg)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", arg, err)
+		}
+		if matches == nil {
+			return nil, fmt.Errorf("%s: no such file", arg)
+		}
+
+		for _, match := range matches {
+			info, err := os.Stat(match)
+			if err != nil {
+				return nil, err
+			}
+			if !info.IsDir() {
+				inputs = append(inputs, input{path: match, rel: outputName(match)})
+				continue
+			}
+
+			err = filepath.WalkDir(match, func(path string, entry fs.DirEntry, err error) error {
+				if err != nil || entry.IsDir() || !isHTMLFile(path) {
+					return err
+				}
+				rel, err := filepath.Rel(match, path)
+				if err != nil {
+					return err
+				}
+				inputs = append(inputs, input{path: path, rel: strings.TrimSuffix(rel, filepath.Ext(rel))})
+				return nil
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return inputs, nil
+}
+
+// outputName returns the mirrored output path of a file given on the command
+// line: its relative path, or its base name when the path is absolute or
+// leaves the working directory.
+func outputName(path string) string {
+	name := filepath.Clean(path)
+	if !filepath.IsLocal(name) {
+		name = filepath.Base(name)
+	}
+	return strings.TrimSuffix(name, filepath.Ext(name))
+}
+
+// isHTMLFile reports whether a path has an HTML file extension.
+func isHTMLFile(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".html", ".htm", ".xhtml":
+		return true
+	}
+	return false
+}
+
+// convertInput reads and converts a single input.
+func convertInput(converter *gomarkdownify.Converter, in input, stdin io.Reader) (string, error) {
+	var data []byte
+	var err error
+	if in.path == "-" {
+		data, err = io.ReadAll(stdin)
+	} else {
+		data, err = os.ReadFile(in.path)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	markdown, err := converter.Convert(string(data))
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", in.path, err)
+	}
+	// End the output with a single newline like other text files
+	return strings.TrimRight(markdown, "\n") + "\n", nil
+}
+
+// writeMirrored writes the Markdown of one input below the output directory,
+// creating parent directories as needed.
+func writeMirrored(dir string, rel string, markdown string) error {
+	path := filepath.Join(dir, rel)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(markdown), 0o644)
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestKebabCase tests the flag names derived from Options fields
+func TestKebabCase(t *testing.T) {
+	tests := map[string]string{
+		"HeadingStyle":          "heading-style",
+		"TOCMinLevel":           "toc-min-level",
+		"TOC":                   "toc",
+		"HeadingIDs":            "heading-ids",
+		"DemoteExtraH1":         "demote-extra-h1",
+		"DisplayMathDelimiters": "display-math-delimiters",
+	}
+	for name, expected := range tests {
+		if result := kebabCase(name); result != expected {
+			t.Errorf("kebabCase(%q): Expected %q, got %q", name, expected, result)
+		}
+	}
+}
+
+// TestRunStdin tests converting standard input with option flags
+func TestRunStdin(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	stdin := strings.NewReader("<h1>Title</h1><p>Some <b>bold</b> text</p><ul><li>a</li></ul>")
+	args := []string{"-heading-style", "atx", "-bullets", "-", "-strong-em-symbol=_", "-strip-document", "strip"}
+
+	if code := run(args, stdin, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	expected := "# Title\n\nSome __bold__ text\n\n- a\n"
+	if stdout.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, stdout.String())
+	}
+}
+
+// TestRunConfig tests loading options from a config file, overridden by flags
+func TestRunConfig(t *testing.T) {
+	dir := t.TempDir()
+	config := filepath.Join(dir, "config.json")
+	err := os.WriteFile(config, []byte(`{"HeadingStyle": "atx", "StripDocument": "strip", "Bullets": "+", "InlineMathDelimiters": ["\\(", "\\)"]}`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	stdin := strings.NewReader("<h2>Title</h2><ul><li>a</li></ul>")
+	if code := run([]string{"-config", config, "-bullets", "*"}, stdin, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	expected := "## Title\n\n* a\n"
+	if stdout.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, stdout.String())
+	}
+
+	// Unknown keys are reported
+	if err := os.WriteFile(config, []byte(`{"HeadingStyel": "atx"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	stderr.Reset()
+	if code := run([]string{"-config", config}, strings.NewReader(""), &stdout, &stderr); code != 1 {
+		t.Errorf("Expected exit code 1, got %d", code)
+	}
+	if !strings.Contains(stderr.String(), "HeadingStyel") {
+		t.Errorf("Expected an unknown field error, got %q", stderr.String())
+	}
+
+	// Invalid values are reported
+	stderr.Reset()
+	if code := run([]string{"-heading-style", "atxx"}, strings.NewReader(""), &stdout, &stderr); code != 2 {
+		t.Errorf("Expected exit code 2, got %d", code)
+	}
+	if !strings.Contains(stderr.String(), `invalid HeadingStyle "atxx"`) {
+		t.Errorf("Expected an invalid option error, got %q", stderr.String())
+	}
+}
+
+// TestRunPresetYAML tests starting from a preset and loading a YAML config file
+func TestRunPresetYAML(t *testing.T) {
+	dir := t.TempDir()
+	config := filepath.Join(dir, "config.yaml")
+	err := os.WriteFile(config, []byte("StripDocument: strip\nMath: true\nInlineMathDelimiters: ['\\(', '\\)']\n"), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	stdin := strings.NewReader("<h2>Title</h2><ul><li>a</li></ul><p><math><mi>x</mi></math></p>")
+	if code := run([]string{"-preset", "gfm", "-config", config}, stdin, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	expected := "## Title\n\n- a\n\n\\(x\\)\n"
+	if stdout.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, stdout.String())
+	}
+
+	// Keys are field names, and unknown keys are reported
+	if err := os.WriteFile(config, []byte("heading_style: atx\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	stderr.Reset()
+	if code := run([]string{"-config", config}, strings.NewReader(""), &stdout, &stderr); code != 1 {
+		t.Errorf("Expected exit code 1, got %d", code)
+	}
+	if !strings.Contains(stderr.String(), "heading_style") {
+		t.Errorf("Expected an unknown field error, got %q", stderr.String())
+	}
+
+	// Unknown presets are reported
+	stderr.Reset()
+	if code := run([]string{"-preset", "github"}, strings.NewReader(""), &stdout, &stderr); code != 2 {
+		t.Errorf("Expected exit code 2, got %d", code)
+	}
+	if !strings.Contains(stderr.String(), `invalid Preset "github"`) {
+		t.Errorf("Expected an invalid preset error, got %q", stderr.String())
+	}
+}
+
+// TestRunFiles tests converting files, globs and directories to an output directory
+func TestRunFiles(t *testing.T) {
+	dir := t.TempDir()
+	site := filepath.Join(dir, "site")
+	files := map[string]string{
+		"index.html":      "<h1>Home</h1>",
+		"docs/guide.htm":  "<h1>Guide</h1>",
+		"docs/style.css":  "body {}",
+		"docs/api/a.html": "<p>API</p>",
+	}
+	for name, content := range files {
+		path := filepath.Join(site, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	out := filepath.Join(dir, "out")
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-heading-style=atx", "-out-dir", out, site}, nil, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+	}
+
+	expected := map[string]string{
+		"index.md":      "# Home\n",
+		"docs/guide.md": "# Guide\n",
+		"docs/api/a.md": "API\n",
+	}
+	for name, content := range expected {
+		data, err := os.ReadFile(filepath.Join(out, name))
+		if err != nil {
+			t.Errorf("Expected %s to be written: %v", name, err)
+			continue
+		}
+		if string(data) != content {
+			t.Errorf("%s: Expected %q, got %q", name, content, string(data))
+		}
+	}
+	if _, err := os.Stat(filepath.Join(out, "docs/style.md")); err == nil {
+		t.Errorf("Expected non-HTML files to be skipped")
+	}
+
+	// A glob written to stdout, and a single file written with -o
+	stdout.Reset()
+	if code := run([]string{"-heading-style=atx", filepath.Join(site, "docs", "*.htm")}, nil, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	if stdout.String() != "# Guide\n" {
+		t.Errorf("Expected %q, got %q", "# Guide\n", stdout.String())
+	}
+
+	target := filepath.Join(dir, "home.md")
+	if code := run([]string{"-o", target, filepath.Join(site, "index.html")}, nil, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	if data, _ := os.ReadFile(target); string(data) != "Home\n====\n" {
+		t.Errorf("Expected %q, got %q", "Home\n====\n", string(data))
+	}
+
+	// Missing files are reported
+	stderr.Reset()
+	if code := run([]string{filepath.Join(dir, "missing.html")}, nil, &stdout, &stderr); code != 1 {
+		t.Errorf("Expected exit code 1, got %d", code)
+	}
+}
+
+// TestRunSite tests converting a site directory
+func TestRunSite(t *testing.T) {
+	dir := t.TempDir()
+	site := filepath.Join(dir, "site")
+	if err := os.MkdirAll(site, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	pages := map[string]string{
+		"index.html": `<p><a href="about.html">About</a> <a href="gone.html">Gone</a></p>`,
+		"about.html": `<p>About</p>`,
+	}
+	for name, content := range pages {
+		if err := os.WriteFile(filepath.Join(site, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	out := filepath.Join(dir, "out")
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-site", "-out-dir", out, site}, nil, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	if data, _ := os.ReadFile(filepath.Join(out, "index.md")); string(data) != "[About](about.md) [Gone](gone.html)\n" {
+		t.Errorf("Unexpected index.md: %q", string(data))
+	}
+	if !strings.Contains(stderr.String(), "broken link gone.html") {
+		t.Errorf("Expected the broken link to be reported, got %q", stderr.String())
+	}
+
+	if code := run([]string{"-site", site}, nil, &stdout, &stderr); code != 2 {
+		t.Errorf("Expected exit code 2 without -out-dir, got %d", code)
+	}
+}
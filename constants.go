@@ -47,3 +47,84 @@ const (
 	// STRIP removes both leading and trailing newlines from the document
 	STRIP = "strip"
 )
+
+// Markdown flavors define which Markdown dialect the output targets. Syntax
+// that is not part of CommonMark (definition lists, task lists, footnotes,
+// etc.) is only emitted when the chosen flavor supports it.
+const (
+	// COMMONMARK targets plain CommonMark without extensions
+	COMMONMARK = "commonmark"
+
+	// GFM targets GitHub Flavored Markdown
+	GFM = "gfm"
+
+	// PANDOC targets Pandoc's Markdown
+	PANDOC = "pandoc"
+
+	// MARKDOWN_EXTRA targets PHP Markdown Extra
+	MARKDOWN_EXTRA = "markdown_extra"
+
+	// KRAMDOWN targets kramdown (used by Jekyll)
+	KRAMDOWN = "kramdown"
+
+	// MKDOCS targets Python-Markdown as configured by MkDocs Material
+	MKDOCS = "mkdocs"
+
+	// OBSIDIAN targets Obsidian Flavored Markdown
+	OBSIDIAN = "obsidian"
+)
+
+// Front matter formats define how document metadata is serialized at the top
+// of the Markdown output.
+const (
+	// YAML writes front matter between "---" lines
+	YAML = "yaml"
+
+	// TOML writes front matter between "+++" lines
+	TOML = "toml"
+
+	// JSON writes front matter as a JSON object
+	JSON = "json"
+)
+
+// Table of contents positions define where the generated table of contents
+// is inserted.
+const (
+	// TOC_PLACEHOLDER replaces a [TOC] placeholder paragraph with the table of contents
+	TOC_PLACEHOLDER = "placeholder"
+
+	// TOC_TOP inserts the table of contents at the top of the document
+	TOC_TOP = "top"
+
+	// TOC_AFTER_H1 inserts the table of contents after the first level 1 heading,
+	// or at the top of the document if there is none
+	TOC_AFTER_H1 = "after_h1"
+)
+
+// Deduplication scopes define which earlier headings a heading is compared
+// with when DeduplicateHeadings is enabled.
+const (
+	// DEDUPLICATE_DOCUMENT treats a heading as a duplicate of any earlier heading
+	// with the same level and text
+	DEDUPLICATE_DOCUMENT = "document"
+
+	// DEDUPLICATE_SECTION treats a heading as a duplicate of an earlier heading with
+	// the same level and text in the same parent section
+	DEDUPLICATE_SECTION = "section"
+
+	// DEDUPLICATE_CONSECUTIVE treats a heading as a duplicate only when the heading
+	// right before it has the same level and text
+	DEDUPLICATE_CONSECUTIVE = "consecutive"
+)
+
+// Details styles define how <details> collapsible sections are converted.
+const (
+	// DETAILS_HTML keeps the <details> and <summary> elements and converts the body to Markdown
+	DETAILS_HTML = "html"
+
+	// DETAILS_CALLOUT converts the section to a callout titled with the summary
+	DETAILS_CALLOUT = "callout"
+
+	// DETAILS_HEADING converts the summary to a heading followed by the body
+	DETAILS_HEADING = "heading"
+)
@@ -1,28 +1,96 @@
 package gomarkdownify
 
 import (
+	"context"
 	"regexp"
+	"slices"
 	"strings"
 
+	"github.com/andybalholm/cascadia"
 	"golang.org/x/net/html"
 )
 
 // Converter is the main struct for converting HTML to Markdown.
 // It holds the configuration options and provides methods for
 // transforming HTML content into Markdown format.
+//
+// A Converter is not modified by conversions, so it can be shared by
+// multiple goroutines converting documents at the same time.
 type Converter struct {
 	options Options
-	// Track processed headings for deduplication
-	processedHeadings map[string]bool
+	// Compiled Options.Include and Options.Exclude selectors
+	include cascadia.SelectorGroup
+	exclude cascadia.SelectorGroup
+	// State of the conversion in progress. The Converter created by
+	// NewConverter has none; every conversion runs on a copy with its own state.
+	state *conversion
+}
+
+// conversion holds the state of a single conversion.
+type conversion struct {
+	// Footnote references and definitions found in the document
+	footnotes *footnoteIndex
+	// The parsed document
+	doc *html.Node
+	// The main content element kept by Readability
+	mainContent *html.Node
+	// Headings, links, images and warnings collected for ConvertResult
+	headings []*Heading
+	links    []Link
+	images   []Image
+	warnings []string
+	// Markdown levels of the headings, when they differ from the HTML
+	headingLevels map[*html.Node]int
+	// Headings that repeat an earlier heading
+	duplicateHeadings map[*html.Node]bool
+	// Anchors of the headings
+	anchors *anchorIndex
+	// Callouts found in the document
+	callouts *calloutIndex
+	// Converted text of the <details> summaries
+	summaries map[*html.Node]string
+	// Context of the conversion, checked every cancelCheckInterval nodes
+	ctx   context.Context
+	nodes int
+	// First error stopping the conversion, after which nodes convert to ""
+	err error
 }
 
 // NewConverter creates a new Converter with the given options.
 // This is the factory function for creating a Converter instance.
-func NewConverter(options Options) *Converter {
-	return &Converter{
-		options:           options,
-		processedHeadings: make(map[string]bool),
+//
+// The converter starts from DefaultOptions and applies the options in
+// order, so an Options struct replaces everything before it and the With
+// functions change single options:
+//
+//	converter, err := NewConverter(DefaultOptions())
+//	converter, err := NewConverter(WithHeadingStyle(ATX), WithBullets("-"))
+//	converter, err := NewConverter(WithPreset(GFM), WithWrap(80))
+//
+// The resulting options are checked with Options.Validate, and invalid
+// options are reported in the returned error instead of being silently
+// ignored.
+func NewConverter(options ...Option) (*Converter, error) {
+	resolved := DefaultOptions()
+	for _, option := range options {
+		if option == nil {
+			continue
+		}
+		if err := option.apply(&resolved); err != nil {
+			return nil, err
+		}
+	}
+	if err := resolved.Validate(); err != nil {
+		return nil, err
 	}
+	// Validate has checked that the selectors parse
+	include, _ := compileSelectors(resolved.Include)
+	exclude, _ := compileSelectors(resolved.Exclude)
+	return &Converter{
+		options: resolved,
+		include: include,
+		exclude: exclude,
+	}, nil
 }
 
 // Convert converts HTML to Markdown using the converter's options.
@@ -39,16 +107,118 @@ func NewConverter(options Options) *Converter {
 // Special cases are handled for common HTML patterns to ensure
 // compatibility with the Python markdownify package.
 func (c *Converter) Convert(htmlContent string) (string, error) {
-	// Reset processed headings for each conversion
-	c.processedHeadings = make(map[string]bool)
-	
+	return c.ConvertContext(context.Background(), htmlContent)
+}
+
+// ConvertContext converts HTML to Markdown like Convert, and stops when ctx
+// is cancelled or its deadline passes, returning ctx.Err().
+//
+// Together with the MaxInputSize, MaxDepth and MaxOutputSize options, it
+// bounds the work done on untrusted input. Exceeding a limit returns a
+// *LimitError wrapping ErrInputTooLarge, ErrNestingTooDeep or
+// ErrOutputTooLarge.
+//
+// Example:
+//
+//	options := gomarkdownify.DefaultOptions()
+//	options.MaxInputSize = 5 << 20
+//	options.MaxDepth = 256
+//	converter, err := gomarkdownify.NewConverter(options)
+//	if err != nil {
+//	    // handle error
+//	}
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+//	defer cancel()
+//	markdown, err := converter.ConvertContext(ctx, html)
+//	if errors.Is(err, gomarkdownify.ErrNestingTooDeep) {
+//	    // reject the document
+//	}
+func (c *Converter) ConvertContext(ctx context.Context, htmlContent string) (string, error) {
+	markdown, _, err := c.convert(ctx, htmlContent)
+	return markdown, err
+}
+
+// convert converts HTML to Markdown on a copy of the converter with a new
+// conversion state, and returns the state for ConvertResult.
+func (c *Converter) convert(ctx context.Context, htmlContent string) (string, *conversion, error) {
+	run := &Converter{
+		options: c.options,
+		include: c.include,
+		exclude: c.exclude,
+		state: &conversion{
+			summaries: make(map[*html.Node]string),
+			ctx:       ctx,
+		},
+	}
+	markdown, err := run.convertDocument(htmlContent)
+	if err != nil {
+		return "", run.state, err
+	}
+	if c.options.MaxOutputSize > 0 && len(markdown) > c.options.MaxOutputSize {
+		return "", run.state, &LimitError{Err: ErrOutputTooLarge, Limit: c.options.MaxOutputSize}
+	}
+	return markdown, run.state, nil
+}
+
+// convertDocument performs the steps of Convert using the converter's state.
+func (c *Converter) convertDocument(htmlContent string) (string, error) {
+	if c.options.MaxInputSize > 0 && len(htmlContent) > c.options.MaxInputSize {
+		return "", &LimitError{Err: ErrInputTooLarge, Limit: c.options.MaxInputSize}
+	}
+	if c.options.MaxDepth > 0 && markupDepth(htmlContent) > c.options.MaxDepth {
+		return "", &LimitError{Err: ErrNestingTooDeep, Limit: c.options.MaxDepth}
+	}
+	if err := c.state.ctx.Err(); err != nil {
+		return "", err
+	}
+
 	doc, err := html.Parse(strings.NewReader(htmlContent))
 	if err != nil {
 		return "", err
 	}
 
-	var parentTags []string
-	result := c.processNode(doc, parentTags)
+	// The parser may add elements, check the tree before any recursive pass
+	if c.options.MaxDepth > 0 && documentDepth(doc) > c.options.MaxDepth {
+		return "", &LimitError{Err: ErrNestingTooDeep, Limit: c.options.MaxDepth}
+	}
+	if err := c.state.ctx.Err(); err != nil {
+		return "", err
+	}
+
+	// Drop the parts of the document the selectors leave out, then keep
+	// only the main content
+	c.filterDocument(doc)
+	if c.options.Readability {
+		c.state.mainContent = c.extractMainContent(doc)
+	}
+
+	// Analyze the document before converting it
+	c.state.doc = doc
+	c.state.headingLevels = c.computeHeadingLevels(doc)
+	c.state.duplicateHeadings = c.findDuplicateHeadings(doc)
+	c.state.anchors = c.collectHeadingAnchors(doc)
+
+	// Find footnotes so references and definitions can be rewritten
+	if c.options.Footnotes && supportsFootnotes(c.flavor()) {
+		c.state.footnotes = findFootnotes(doc)
+	}
+
+	// Find callouts so they can be converted to the flavor's syntax
+	if c.options.Callouts {
+		c.state.callouts = c.findCallouts(doc)
+	}
+
+	var parents tagContext
+	result := c.processNode(doc, parents)
+	if c.state.err != nil {
+		return "", c.state.err
+	}
+
+	// Collect footnote definitions at the end of the document
+	if c.state.footnotes != nil {
+		result = strings.TrimRight(result, " \t\r\n") + "\n\n" + c.renderFootnotes() + "\n"
+	}
 
 	// Normalize multiple consecutive newlines if enabled
 	if c.options.NormalizeNewlines {
@@ -92,6 +262,18 @@ func (c *Converter) Convert(htmlContent string) (string, error) {
 	// Normalize multiple consecutive newlines
 	result = regexp.MustCompile(`\n{3,}`).ReplaceAllString(result, "\n\n")
 
+	// Insert the table of contents
+	if c.options.TOC != "" {
+		result = c.insertTOC(result)
+	}
+
+	// Prepend the front matter block
+	if c.options.FrontMatter != "" {
+		if frontMatter := c.renderFrontMatter(doc); frontMatter != "" {
+			result = frontMatter + strings.TrimLeft(result, "\n")
+		}
+	}
+
 	return result, nil
 }
 
@@ -108,19 +290,31 @@ func (c *Converter) Convert(htmlContent string) (string, error) {
 //
 // Parameters:
 //   - n: The HTML node to process
-//   - parentTags: A list of parent tag names, used for context-aware conversion
+//   - parents: The context of the ancestors, used for context-aware conversion
 //
 // Returns:
 //   - A string containing the Markdown representation of the node
-func (c *Converter) processNode(n *html.Node, parentTags []string) string {
+func (c *Converter) processNode(n *html.Node, parents tagContext) string {
+	// Stop converting once the conversion is cancelled or over a limit
+	if c.state.err != nil {
+		return ""
+	}
+	c.state.nodes++
+	if c.state.nodes%cancelCheckInterval == 0 {
+		if err := c.state.ctx.Err(); err != nil {
+			c.state.err = err
+			return ""
+		}
+	}
+
 	if n.Type == html.TextNode {
-		return c.processText(n, parentTags)
+		return c.processText(n, parents)
 	} else if n.Type == html.ElementNode {
-		return c.processElement(n, parentTags)
+		return c.processElement(n, parents)
 	} else if n.Type == html.DocumentNode {
 		var result strings.Builder
 		for child := n.FirstChild; child != nil; child = child.NextSibling {
-			result.WriteString(c.processNode(child, parentTags))
+			result.WriteString(c.processNode(child, parents))
 		}
 		return result.String()
 	} else if n.Type == html.CommentNode {
@@ -150,35 +344,63 @@ func (c *Converter) processNode(n *html.Node, parentTags []string) string {
 //
 // Parameters:
 //   - n: The HTML element node to process
-//   - parentTags: A list of parent tag names, used for context-aware conversion
+//   - parents: The context of the ancestors, used for context-aware conversion
 //
 // Returns:
 //   - A string containing the Markdown representation of the element
-func (c *Converter) processElement(n *html.Node, parentTags []string) string {
-	// Create a copy of parent tags and add this tag
-	newParentTags := make([]string, len(parentTags))
-	copy(newParentTags, parentTags)
-	newParentTags = append(newParentTags, n.Data)
+func (c *Converter) processElement(n *html.Node, parents tagContext) string {
+	// Rewrite footnote references and drop the original definitions
+	if c.state.footnotes != nil {
+		if label, ok := c.state.footnotes.refs[n]; ok {
+			return "[^" + label + "]"
+		}
+		if c.state.footnotes.skip[n] {
+			return ""
+		}
+	}
 
-	// Add special parent pseudo-tags
-	if reHTMLHeading.MatchString(n.Data) || n.Data == "td" || n.Data == "th" {
-		newParentTags = append(newParentTags, "_inline")
+	// Callout titles are part of the callout markup
+	if c.state.callouts != nil && c.state.callouts.skip[n] {
+		return ""
 	}
-	if n.Data == "pre" || n.Data == "code" || n.Data == "kbd" || n.Data == "samp" {
-		newParentTags = append(newParentTags, "_noformat")
+
+	// The head is represented by the front matter, don't repeat the title.
+	// With Include or Readability, only the selected content of the body is converted.
+	if n.Data == "head" && (c.options.FrontMatter != "" || c.include != nil || c.options.Readability) {
+		return ""
 	}
 
-	// Add special tag for inline elements
-	if n.Data == "a" || n.Data == "img" || n.Data == "b" || n.Data == "strong" ||
-		n.Data == "i" || n.Data == "em" || n.Data == "code" || n.Data == "del" ||
-		n.Data == "s" || n.Data == "sub" || n.Data == "sup" {
-		newParentTags = append(newParentTags, "_inline_element")
+	// Convert equations to TeX and drop their rendered form
+	if c.options.Math {
+		if tex, display, ok := extractMath(n); ok {
+			return c.convertMath(tex, display, parents)
+		}
+		if isMathRendering(n) {
+			return ""
+		}
 	}
 
+	// Describe this element to its children
+	childParents := parents.enter(n, c.options.KeepInlineImagesIn)
+
+	// Links and images are recorded as the children convert; remember where
+	// this element's start, so a dropped heading or summary can take them back
+	links, images, headings := len(c.state.links), len(c.state.images), len(c.state.headings)
+
 	// Process children
 	var childrenText strings.Builder
 	for child := n.FirstChild; child != nil; child = child.NextSibling {
-		childrenText.WriteString(c.processNode(child, newParentTags))
+		childrenText.WriteString(c.processNode(child, childParents))
+		if child.Type == html.ElementNode && child.Data == "li" {
+			childParents.item++
+		}
+	}
+	if c.options.MaxOutputSize > 0 && childrenText.Len() > c.options.MaxOutputSize &&
+		len(strings.TrimSpace(childrenText.String())) > c.options.MaxOutputSize {
+		c.state.err = &LimitError{Err: ErrOutputTooLarge, Limit: c.options.MaxOutputSize}
+	}
+	if c.state.err != nil {
+		return ""
 	}
 
 	// Skip style and script tags completely
@@ -188,56 +410,97 @@ func (c *Converter) processElement(n *html.Node, parentTags []string) string {
 
 	// Check if we should convert this tag
 	shouldConvert := c.shouldConvertTag(n.Data)
+	var co callout
+	isCallout := false
+	if c.state.callouts != nil {
+		co, isCallout = c.state.callouts.callouts[n]
+	}
+	text := childrenText.String()
+	if reHTMLHeading.MatchString(n.Data) && (!shouldConvert || isCallout) {
+		// Only convertH resolves the line breaks of a heading
+		text = strings.ReplaceAll(text, headingLineBreak, " ")
+	}
 	if !shouldConvert {
-		return childrenText.String()
+		return text
 	}
 
 	// Apply tag-specific conversion
-	text := childrenText.String()
+	if isCallout {
+		return c.convertCallout(co, text, parents)
+	}
 	switch n.Data {
 	case "a":
-		return c.convertA(n, text, parentTags)
+		return c.convertA(n, text, parents)
 	case "b", "strong":
-		return c.convertB(n, text, parentTags)
+		return c.convertB(n, text, parents)
 	case "blockquote":
-		return c.convertBlockquote(n, text, parentTags)
+		return c.convertBlockquote(n, text, parents)
 	case "br":
-		return c.convertBr(n, text, parentTags)
+		return c.convertBr(n, text, parents)
 	case "code", "kbd", "samp":
-		return c.convertCode(n, text, parentTags)
+		return c.convertCode(n, text, parents)
 	case "del", "s":
-		return c.convertDel(n, text, parentTags)
+		return c.convertDel(n, text, parents)
+	case "dd":
+		return c.convertDd(n, text, parents)
+	case "details":
+		body := len(c.state.headings)
+		markdown := c.convertDetails(n, text, parents)
+		if recorded := len(c.state.headings); recorded > body {
+			// The summary heading precedes the headings of the body
+			summary := c.state.headings[recorded-1]
+			c.state.headings = slices.Insert(c.state.headings[:recorded-1], headings, summary)
+		}
+		return markdown
 	case "div", "article", "section":
-		return c.convertDiv(n, text, parentTags)
+		return c.convertDiv(n, text, parents)
+	case "dl":
+		return c.convertDl(n, text, parents)
+	case "dt":
+		return c.convertDt(n, text, parents)
 	case "em", "i":
-		return c.convertEm(n, text, parentTags)
+		return c.convertEm(n, text, parents)
 	case "h1", "h2", "h3", "h4", "h5", "h6":
 		level := int(n.Data[1] - '0')
-		return c.convertH(level, n, text, parentTags)
+		markdown := c.convertH(level, n, text, parents)
+		if markdown == "" && c.state.duplicateHeadings[n] {
+			// The links and images of a dropped duplicate are not in the output
+			c.state.links = c.state.links[:links]
+			c.state.images = c.state.images[:images]
+		}
+		return markdown
 	case "hr":
-		return c.convertHr(n, text, parentTags)
+		return c.convertHr(n, text, parents)
 	case "img":
-		return c.convertImg(n, text, parentTags)
+		return c.convertImg(n, text, parents)
 	case "li":
-		return c.convertLi(n, text, parentTags)
+		return c.convertLi(n, text, parents)
 	case "ol", "ul":
-		return c.convertList(n, text, parentTags)
+		return c.convertList(n, text, parents)
 	case "p":
-		return c.convertP(n, text, parentTags)
+		return c.convertP(n, text, parents)
 	case "pre":
-		return c.convertPre(n, text, parentTags)
+		return c.convertPre(n, text, parents)
 	case "sub":
-		return c.convertSub(n, text, parentTags)
+		return c.convertSub(n, text, parents)
+	case "summary":
+		markdown := c.convertSummary(n, text, parents)
+		if _, kept := c.state.summaries[n]; kept && c.options.Details != DETAILS_HEADING && !parents.has(inInline) {
+			// convertDetails writes this summary as plain text, without its links and images
+			c.state.links = c.state.links[:links]
+			c.state.images = c.state.images[:images]
+		}
+		return markdown
 	case "sup":
-		return c.convertSup(n, text, parentTags)
+		return c.convertSup(n, text, parents)
 	case "table":
-		return c.convertTable(n, text, parentTags)
+		return c.convertTable(n, text, parents)
 	case "td":
-		return c.convertTd(n, text, parentTags)
+		return c.convertTd(n, text, parents)
 	case "th":
-		return c.convertTh(n, text, parentTags)
+		return c.convertTh(n, text, parents)
 	case "tr":
-		return c.convertTr(n, text, parentTags)
+		return c.convertTr(n, text, parents)
 	default:
 		// For unknown tags, just return the text
 		return text
@@ -255,15 +518,15 @@ func (c *Converter) processElement(n *html.Node, parentTags []string) string {
 //
 // Parameters:
 //   - n: The HTML text node to process
-//   - parentTags: A list of parent tag names, used for context-aware conversion
+//   - parents: The context of the ancestors, used for context-aware conversion
 //
 // Returns:
 //   - A string containing the Markdown representation of the text
-func (c *Converter) processText(n *html.Node, parentTags []string) string {
+func (c *Converter) processText(n *html.Node, parents tagContext) string {
 	text := n.Data
 
 	// Normalize whitespace if not in a preformatted element
-	if !contains(parentTags, "pre") {
+	if !parents.has(inPre) {
 		if c.options.Wrap {
 			text = reAllWhitespace.ReplaceAllString(text, " ")
 		} else {
@@ -273,8 +536,8 @@ func (c *Converter) processText(n *html.Node, parentTags []string) string {
 	}
 
 	// Escape special characters if not in a preformatted or code element
-	if !contains(parentTags, "_noformat") {
-		text = c.escape(text, parentTags)
+	if !parents.has(inNoFormat) {
+		text = c.escape(text, parents)
 	}
 
 	// Handle whitespace around block elements
@@ -361,11 +624,11 @@ func (c *Converter) shouldConvertTag(tagName string) bool {
 //
 // Parameters:
 //   - text: The text to escape
-//   - parentTags: A list of parent tag names, used for context-aware escaping
+//   - parents: The context of the ancestors, used for context-aware escaping
 //
 // Returns:
 //   - The escaped text
-func (c *Converter) escape(text string, parentTags []string) string {
+func (c *Converter) escape(text string, parents tagContext) string {
 	if text == "" {
 		return ""
 	}
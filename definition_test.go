@@ -0,0 +1,66 @@
+package gomarkdownify
+
+import (
+	"testing"
+)
+
+// TestDefinitionLists tests the conversion of <dl>, <dt> and <dd> tags
+func TestDefinitionLists(t *testing.T) {
+	html := `<dl>
+		<dt>Apple</dt>
+		<dt>Pomme</dt>
+		<dd>A fruit.</dd>
+		<dd><p>Red.</p><p>Or green.</p></dd>
+		<dt>Go</dt>
+		<dd>A language</dd>
+	</dl>`
+
+	tests := []struct {
+		flavor   string
+		expected string
+	}{
+		{PANDOC, "Apple\nPomme\n: A fruit.\n: Red.\n\n    Or green.\n\nGo\n: A language"},
+		{MARKDOWN_EXTRA, "Apple\nPomme\n: A fruit.\n: Red.\n\n    Or green.\n\nGo\n: A language"},
+		{GFM, "**Apple**  \n**Pomme**\n\n  A fruit.\n\n  Red.\n\n  Or green.\n\n**Go**\n\n  A language"},
+		{COMMONMARK, "**Apple**  \n**Pomme**\n\n  A fruit.\n\n  Red.\n\n  Or green.\n\n**Go**\n\n  A language"},
+	}
+
+	for _, test := range tests {
+		options := DefaultOptions()
+		options.Flavor = test.flavor
+		options.StripDocument = STRIP
+
+		result, err := Convert(html, options)
+		if err != nil {
+			t.Fatalf("Failed to convert HTML: %v", err)
+		}
+		if result != test.expected {
+			t.Errorf("Flavor %s: Expected %q, got %q", test.flavor, test.expected, result)
+		}
+	}
+
+	// Test that the list is separated from surrounding blocks
+	options := DefaultOptions()
+	options.Flavor = PANDOC
+	options.StripDocument = STRIP
+	result, err := Convert("<p>Before</p><dl><dt>Term</dt><dd>Definition</dd></dl><p>After</p>", options)
+	if err != nil {
+		t.Fatalf("Failed to convert HTML: %v", err)
+	}
+	expected := "Before\n\nTerm\n: Definition\n\nAfter"
+	if result != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+
+	// Test the backslash line break between terms in the fallback
+	options.Flavor = COMMONMARK
+	options.NewlineStyle = BACKSLASH
+	result, err = Convert("<dl><dt>A</dt><dt>B</dt><dd>Letters</dd></dl>", options)
+	if err != nil {
+		t.Fatalf("Failed to convert HTML: %v", err)
+	}
+	expected = "**A**\\\n**B**\n\n  Letters"
+	if result != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+}
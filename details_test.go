@@ -0,0 +1,141 @@
+package gomarkdownify
+
+import (
+	"testing"
+)
+
+// TestDetails tests converting <details> collapsible sections
+func TestDetails(t *testing.T) {
+	input := "<h2>Intro</h2><details><summary>Click <b>me</b></summary><p>Body <i>x</i></p><ul><li>a</li></ul></details><p>after</p>"
+
+	tests := []struct {
+		name     string
+		html     string
+		details  string
+		flavor   string
+		expected string
+	}{
+		{
+			name:     "HTML",
+			html:     input,
+			details:  DETAILS_HTML,
+			flavor:   GFM,
+			expected: "## Intro\n\n<details>\n<summary>Click me</summary>\n\nBody *x*\n\n* a\n\n</details>\n\nafter",
+		},
+		{
+			name:     "HTML open without summary",
+			html:     "<details open>Just <b>text</b></details>",
+			details:  DETAILS_HTML,
+			flavor:   GFM,
+			expected: "<details open>\n<summary>Details</summary>\n\nJust **text**\n\n</details>",
+		},
+		{
+			name:     "HTML escapes the summary",
+			html:     "<details><summary>a &lt; b</summary></details>",
+			details:  DETAILS_HTML,
+			flavor:   GFM,
+			expected: "<details>\n<summary>a &lt; b</summary>\n\n</details>",
+		},
+		{
+			name:     "GitHub callout",
+			html:     input,
+			details:  DETAILS_CALLOUT,
+			flavor:   GFM,
+			expected: "## Intro\n\n> [!NOTE]\n> **Click me**\n>\n> Body *x*\n>\n> * a\n\nafter",
+		},
+		{
+			name:     "MkDocs collapsible admonition",
+			html:     input,
+			details:  DETAILS_CALLOUT,
+			flavor:   MKDOCS,
+			expected: "## Intro\n\n??? note \"Click me\"\n\n    Body *x*\n\n    * a\n\nafter",
+		},
+		{
+			name:     "MkDocs open admonition",
+			html:     "<details open><summary>More</summary>Text</details>",
+			details:  DETAILS_CALLOUT,
+			flavor:   MKDOCS,
+			expected: "???+ note \"More\"\n\n    Text",
+		},
+		{
+			name:     "Obsidian foldable callout",
+			html:     input,
+			details:  DETAILS_CALLOUT,
+			flavor:   OBSIDIAN,
+			expected: "## Intro\n\n> [!note]- Click me\n> Body *x*\n>\n> * a\n\nafter",
+		},
+		{
+			name:     "Heading below the preceding heading",
+			html:     input,
+			details:  DETAILS_HEADING,
+			flavor:   GFM,
+			expected: "## Intro\n\n### Click **me**\n\nBody *x*\n\n* a\n\nafter",
+		},
+		{
+			name:     "Heading without preceding heading",
+			html:     "<details><summary>More</summary>Text</details>",
+			details:  DETAILS_HEADING,
+			flavor:   GFM,
+			expected: "# More\n\nText",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := DefaultOptions()
+			options.HeadingStyle = ATX
+			options.StripDocument = STRIP
+			options.Details = tt.details
+			options.Flavor = tt.flavor
+			result, err := Convert(tt.html, options)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+// TestDetailsHeadingOutline tests the level and anchor of summaries converted to headings
+func TestDetailsHeadingOutline(t *testing.T) {
+	tests := []struct {
+		name     string
+		html     string
+		expected string
+	}{
+		{
+			name:     "Headings in the body",
+			html:     `<h2>Sec</h2><details><summary>More</summary><h3>Inner</h3><p>x</p></details>`,
+			expected: "* [Sec](#sec)\n  + [More](#more)\n  + [Inner](#inner)\n\n## Sec\n\n### More\n\n### Inner\n\nx",
+		},
+		{
+			name:     "Duplicated summary text",
+			html:     `<h2>Setup</h2><details><summary>Setup</summary><p>x</p></details>`,
+			expected: "* [Setup](#setup)\n  + [Setup](#setup-1)\n\n## Setup\n\n### Setup\n\nx",
+		},
+		{
+			name:     "Sibling and nested details",
+			html:     `<h2>A</h2><details><summary>One</summary><details><summary>Two</summary>y</details></details><details>z</details><h3>B</h3>`,
+			expected: "* [A](#a)\n  + [One](#one)\n    - [Two](#two)\n  + [Details](#details)\n  + [B](#b)\n\n## A\n\n### One\n\n#### Two\n\ny\n\n### Details\n\nz\n\n### B",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			converter, err := NewConverter(WithDetails(DETAILS_HEADING), WithHeadingStyle(ATX),
+				WithTOC(TOC_TOP, 1, 6), WithStripDocument(STRIP))
+			if err != nil {
+				t.Fatal(err)
+			}
+			result, err := converter.Convert(tt.html)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
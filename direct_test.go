@@ -48,12 +48,12 @@ func TestDirectSubSupConversion(t *testing.T) {
 	opts := DefaultOptions()
 	opts.SubSymbol = "~"
 	opts.SupSymbol = "^"
-	converter := NewConverter(opts)
+	converter := newTestConverter(t, opts)
 
 	// Test convertSub
 	subHTML := `<sub>test</sub>`
 	subNode := parseHTMLAndGetNode(t, subHTML, "sub")
-	result := converter.convertSub(subNode, "test", nil)
+	result := converter.convertSub(subNode, "test", tagContext{})
 	expected := "~test~"
 	if result != expected {
 		t.Errorf("convertSub: Expected %q, got %q", expected, result)
@@ -62,7 +62,7 @@ func TestDirectSubSupConversion(t *testing.T) {
 	// Test convertSup
 	supHTML := `<sup>test</sup>`
 	supNode := parseHTMLAndGetNode(t, supHTML, "sup")
-	result = converter.convertSup(supNode, "test", nil)
+	result = converter.convertSup(supNode, "test", tagContext{})
 	expected = "^test^"
 	if result != expected {
 		t.Errorf("convertSup: Expected %q, got %q", expected, result)
@@ -71,8 +71,8 @@ func TestDirectSubSupConversion(t *testing.T) {
 	// Test convertSub with empty SubSymbol
 	opts = DefaultOptions()
 	opts.SubSymbol = ""
-	converter = NewConverter(opts)
-	result = converter.convertSub(subNode, "test", nil)
+	converter = newTestConverter(t, opts)
+	result = converter.convertSub(subNode, "test", tagContext{})
 	expected = "test"
 	if result != expected {
 		t.Errorf("convertSub with empty SubSymbol: Expected %q, got %q", expected, result)
@@ -81,8 +81,8 @@ func TestDirectSubSupConversion(t *testing.T) {
 	// Test convertSup with empty SupSymbol
 	opts = DefaultOptions()
 	opts.SupSymbol = ""
-	converter = NewConverter(opts)
-	result = converter.convertSup(supNode, "test", nil)
+	converter = newTestConverter(t, opts)
+	result = converter.convertSup(supNode, "test", tagContext{})
 	expected = "test"
 	if result != expected {
 		t.Errorf("convertSup with empty SupSymbol: Expected %q, got %q", expected, result)
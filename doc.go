@@ -33,12 +33,13 @@ The package supports conversion of most common HTML tags to their Markdown equiv
 
   - Headings (h1-h6)
   - Paragraphs (p)
-  - Lists (ul, ol, li)
+  - Lists (ul, ol, li), including task list items with checkboxes
   - Links (a)
   - Images (img)
   - Blockquotes (blockquote)
   - Code blocks (pre, code)
   - Tables (table, tr, th, td)
+  - Definition lists (dl, dt, dd)
   - Inline formatting (b, strong, i, em, code, del, s, sub, sup)
   - Horizontal rules (hr)
   - Line breaks (br)
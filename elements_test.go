@@ -451,6 +451,7 @@ func TestEscaping(t *testing.T) {
 
 	// Test with EscapeAsterisks = false
 	result = md("*hey*dude*", Options{
+		Bullets:         "*+-",
 		EscapeAsterisks: false,
 	})
 	expected = "*hey*dude*"
@@ -467,6 +468,7 @@ func TestEscaping(t *testing.T) {
 
 	// Test with EscapeUnderscores = false
 	result = md("_hey_dude_", Options{
+		Bullets:           "*+-",
 		EscapeUnderscores: false,
 	})
 	expected = "_hey_dude_"
@@ -476,6 +478,7 @@ func TestEscaping(t *testing.T) {
 
 	// Test with EscapeMisc = true
 	opts := Options{
+		Bullets:    "*+-",
 		EscapeMisc: true,
 	}
 	result = md("# foo", opts)
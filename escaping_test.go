@@ -14,6 +14,7 @@ func TestAsterisks(t *testing.T) {
 
 	// Test with EscapeAsterisks = false
 	result = md("*hey*dude*", Options{
+		Bullets:         "*+-",
 		EscapeAsterisks: false,
 	})
 	expected = "*hey*dude*"
@@ -32,6 +33,7 @@ func TestUnderscores(t *testing.T) {
 
 	// Test with EscapeUnderscores = false
 	result = md("_hey_dude_", Options{
+		Bullets:           "*+-",
 		EscapeUnderscores: false,
 	})
 	expected = "_hey_dude_"
@@ -43,6 +45,7 @@ func TestUnderscores(t *testing.T) {
 func TestXMLEntities(t *testing.T) {
 	// Test with EscapeMisc = true
 	result := md("&amp;", Options{
+		Bullets:    "*+-",
 		EscapeMisc: true,
 	})
 	expected := "\\&"
@@ -72,6 +75,7 @@ func TestHexadecimalEntities(t *testing.T) {
 func TestSingleEscapingEntities(t *testing.T) {
 	// Test with EscapeMisc = true
 	result := md("&amp;amp;", Options{
+		Bullets:    "*+-",
 		EscapeMisc: true,
 	})
 	expected := "\\&amp;"
@@ -83,6 +87,7 @@ func TestSingleEscapingEntities(t *testing.T) {
 func TestMisc(t *testing.T) {
 	// Test with EscapeMisc = true
 	opts := Options{
+		Bullets:    "*+-",
 		EscapeMisc: true,
 	}
 
@@ -336,6 +341,7 @@ func TestMisc(t *testing.T) {
 
 	// Test with EscapeMisc = false
 	result = md("\\ &lt;foo> &amp;amp; | ` `", Options{
+		Bullets:    "*+-",
 		EscapeMisc: false,
 	})
 	expected = "\\ <foo> &amp; | ` `"
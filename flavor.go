@@ -0,0 +1,84 @@
+package gomarkdownify
+
+// flavor returns the configured Flavor, or GFM, the default, when it is empty.
+// The supports functions below are called with this value, so an empty
+// Flavor gets the same dialect everywhere.
+func (c *Converter) flavor() string {
+	if c.options.Flavor == "" {
+		return GFM
+	}
+	return c.options.Flavor
+}
+
+// supportsDefinitionLists reports whether the given flavor has a definition
+// list syntax ("Term\n: Definition").
+//
+// Parameters:
+//   - flavor: The Markdown flavor to check.
+//
+// Returns:
+//   - true if definition lists can be emitted, false otherwise.
+func supportsDefinitionLists(flavor string) bool {
+	switch flavor {
+	case PANDOC, MARKDOWN_EXTRA, KRAMDOWN, MKDOCS:
+		return true
+	}
+	return false
+}
+
+// supportsFootnotes reports whether the given flavor has footnote syntax
+// ("[^1]" references and "[^1]: text" definitions).
+//
+// Parameters:
+//   - flavor: The Markdown flavor to check.
+//
+// Returns:
+//   - true if footnotes can be emitted, false otherwise.
+func supportsFootnotes(flavor string) bool {
+	return flavor != COMMONMARK
+}
+
+// supportsTaskLists reports whether the given flavor has task list items
+// ("- [x] Done").
+//
+// Parameters:
+//   - flavor: The Markdown flavor to check.
+//
+// Returns:
+//   - true if task list markers can be emitted, false otherwise.
+func supportsTaskLists(flavor string) bool {
+	switch flavor {
+	case GFM, PANDOC, MKDOCS, OBSIDIAN:
+		return true
+	}
+	return false
+}
+
+// supportsHeadingAttributes reports whether the given flavor supports
+// attribute blocks on headings ("## Heading {#id}").
+//
+// Parameters:
+//   - flavor: The Markdown flavor to check.
+//
+// Returns:
+//   - true if heading ids can be written as attributes, false otherwise.
+func supportsHeadingAttributes(flavor string) bool {
+	switch flavor {
+	case PANDOC, MARKDOWN_EXTRA, KRAMDOWN, MKDOCS:
+		return true
+	}
+	return false
+}
+
+// generatesHeadingIDs reports whether processors of the given flavor give
+// headings an id of their own, so that links can point at a heading without
+// an explicit id. PHP Markdown Extra only has the ids written as attributes.
+//
+// Parameters:
+//   - flavor: The Markdown flavor to check.
+//
+// Returns:
+//   - true if headings get automatic ids, false otherwise.
+func generatesHeadingIDs(flavor string) bool {
+	return flavor != MARKDOWN_EXTRA
+}
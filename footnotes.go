@@ -0,0 +1,257 @@
+package gomarkdownify
+
+import (
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// footnoteContainerClasses lists the classes of elements that hold footnote
+// definitions in common HTML generators (Pandoc and Hugo "footnotes",
+// Wikipedia "reflist"/"references", WordPress "wp-block-footnotes").
+var footnoteContainerClasses = []string{
+	"footnotes",
+	"footnote-list",
+	"reflist",
+	"references",
+	"wp-block-footnotes",
+}
+
+// footnoteBacklinkClasses lists the classes of links and wrappers that point
+// from a footnote definition back to its reference.
+var footnoteBacklinkClasses = []string{
+	"footnote-back",
+	"footnote-backref",
+	"footnote-return",
+	"reversefootnote",
+	"mw-cite-backlink",
+}
+
+// footnoteIndex records the footnote references and definitions found in a
+// document before conversion.
+type footnoteIndex struct {
+	// refs maps each reference node (the <sup> or <a>) to its footnote label
+	refs map[*html.Node]string
+	// skip holds nodes that are not converted in place (definition
+	// containers and backlinks)
+	skip map[*html.Node]bool
+	// defs lists the definitions in label order
+	defs []footnoteDef
+}
+
+// footnoteDef is a single footnote definition.
+type footnoteDef struct {
+	label string
+	node  *html.Node
+}
+
+// findFootnotes scans a parsed document for footnote references and their
+// definitions.
+//
+// Definitions are <li> elements with an id inside a footnote container.
+// References are links to one of those ids that are wrapped in <sup> or
+// marked as note references (class "footnote-ref" or role "doc-noteref").
+// Labels are assigned sequentially in order of first reference, and
+// unreferenced definitions are numbered after the referenced ones.
+//
+// Parameters:
+//   - doc: The root of the parsed document.
+//
+// Returns:
+//   - The footnote index, or nil if the document has no footnotes.
+func findFootnotes(doc *html.Node) *footnoteIndex {
+	index := &footnoteIndex{
+		refs: make(map[*html.Node]string),
+		skip: make(map[*html.Node]bool),
+	}
+
+	// Collect definitions from footnote containers
+	defsByID := make(map[string]*html.Node)
+	var defOrder []string
+	var findDefs func(n *html.Node, inContainer bool)
+	findDefs = func(n *html.Node, inContainer bool) {
+		if n.Type == html.ElementNode {
+			if !inContainer && isFootnoteContainer(n) {
+				index.skip[n] = true
+				inContainer = true
+			}
+			if inContainer && n.Data == "li" {
+				if id := getAttr(n, "id"); id != "" && defsByID[id] == nil {
+					defsByID[id] = n
+					defOrder = append(defOrder, id)
+				}
+			}
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			findDefs(child, inContainer)
+		}
+	}
+	findDefs(doc, false)
+
+	if len(defsByID) == 0 {
+		return nil
+	}
+
+	// Find references to the definitions, outside of the containers
+	labels := make(map[string]string)
+	var findRefs func(n *html.Node)
+	findRefs = func(n *html.Node) {
+		if index.skip[n] {
+			return
+		}
+		if n.Type == html.ElementNode && n.Data == "a" {
+			href := getAttr(n, "href")
+			if strings.HasPrefix(href, "#") && defsByID[href[1:]] != nil && isFootnoteRef(n) {
+				id := href[1:]
+				label, ok := labels[id]
+				if !ok {
+					label = strconv.Itoa(len(labels) + 1)
+					labels[id] = label
+					index.defs = append(index.defs, footnoteDef{label: label, node: defsByID[id]})
+				}
+
+				// Replace the whole superscript when the link is its only content
+				ref := n
+				if n.Parent != nil && n.Parent.Data == "sup" && onlyElementChild(n.Parent) == n {
+					ref = n.Parent
+				}
+				index.refs[ref] = label
+				return
+			}
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			findRefs(child)
+		}
+	}
+	findRefs(doc)
+
+	if len(index.refs) == 0 {
+		// The containers are ordinary lists, leave them alone
+		return nil
+	}
+
+	// Keep definitions that are never referenced so no content is lost
+	for _, id := range defOrder {
+		if _, ok := labels[id]; !ok {
+			label := strconv.Itoa(len(labels) + 1)
+			labels[id] = label
+			index.defs = append(index.defs, footnoteDef{label: label, node: defsByID[id]})
+		}
+	}
+
+	// Drop the backlinks from the definitions
+	for _, def := range index.defs {
+		markFootnoteBacklinks(def.node, index.skip)
+	}
+
+	return index
+}
+
+// isFootnoteContainer reports whether n is an element holding footnote definitions.
+func isFootnoteContainer(n *html.Node) bool {
+	if getAttr(n, "role") == "doc-endnotes" {
+		return true
+	}
+	for _, class := range footnoteContainerClasses {
+		if hasClass(n, class) {
+			return true
+		}
+	}
+	return false
+}
+
+// isFootnoteRef reports whether the link n is marked up as a footnote reference.
+func isFootnoteRef(n *html.Node) bool {
+	if hasClass(n, "footnote-ref") || getAttr(n, "role") == "doc-noteref" {
+		return true
+	}
+	for p := n.Parent; p != nil && p.Type == html.ElementNode; p = p.Parent {
+		if p.Data == "sup" {
+			return true
+		}
+		if p.Data != "span" && p.Data != "b" && p.Data != "strong" {
+			break
+		}
+	}
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.ElementNode && child.Data == "sup" {
+			return true
+		}
+	}
+	return false
+}
+
+// markFootnoteBacklinks adds the backlinks inside a footnote definition to skip.
+func markFootnoteBacklinks(n *html.Node, skip map[*html.Node]bool) {
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type != html.ElementNode {
+			continue
+		}
+		if isFootnoteBacklink(child) {
+			skip[child] = true
+			continue
+		}
+		markFootnoteBacklinks(child, skip)
+	}
+}
+
+// isFootnoteBacklink reports whether n links from a definition back to its reference.
+func isFootnoteBacklink(n *html.Node) bool {
+	if getAttr(n, "role") == "doc-backlink" {
+		return true
+	}
+	for _, class := range footnoteBacklinkClasses {
+		if hasClass(n, class) {
+			return true
+		}
+	}
+	if n.Data == "a" {
+		href := getAttr(n, "href")
+		return strings.HasPrefix(href, "#fnref") || strings.HasPrefix(href, "#cite_ref") ||
+			(strings.HasPrefix(href, "#") && strings.HasSuffix(href, "-link"))
+	}
+	return false
+}
+
+// onlyElementChild returns the single element child of n if n contains
+// nothing else but whitespace, or nil otherwise.
+func onlyElementChild(n *html.Node) *html.Node {
+	var only *html.Node
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		switch child.Type {
+		case html.ElementNode:
+			if only != nil {
+				return nil
+			}
+			only = child
+		case html.TextNode:
+			if strings.TrimSpace(child.Data) != "" {
+				return nil
+			}
+		}
+	}
+	return only
+}
+
+// renderFootnotes converts the collected footnote definitions to Markdown.
+//
+// Each definition is rendered as "[^label]: text", with continuation lines
+// indented by four spaces so multi-paragraph notes stay attached.
+//
+// Returns:
+//   - The footnote definitions separated by blank lines.
+func (c *Converter) renderFootnotes() string {
+	defs := make([]string, 0, len(c.state.footnotes.defs))
+	for _, def := range c.state.footnotes.defs {
+		var text strings.Builder
+		for child := def.node.FirstChild; child != nil; child = child.NextSibling {
+			text.WriteString(c.processNode(child, tagContext{}))
+		}
+
+		content := strings.Trim(text.String(), " \t\r\n\u00a0")
+		content = reMultipleNewlines.ReplaceAllString(content, "\n\n")
+		defs = append(defs, "[^"+def.label+"]: "+indentLines(content, "    "))
+	}
+	return strings.Join(defs, "\n\n")
+}
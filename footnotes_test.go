@@ -0,0 +1,87 @@
+package gomarkdownify
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestFootnotes tests the conversion of common footnote markup
+func TestFootnotes(t *testing.T) {
+	tests := []struct {
+		name     string
+		html     string
+		expected string
+	}{
+		{
+			name:     "Pandoc",
+			html:     `<p>Pandoc<a href="#fn1" class="footnote-ref" id="fnref1" role="doc-noteref"><sup>1</sup></a> text.</p><section class="footnotes" role="doc-endnotes"><hr /><ol><li id="fn1"><p>The note.<a href="#fnref1" class="footnote-back" role="doc-backlink">↩︎</a></p></li></ol></section>`,
+			expected: "Pandoc[^1] text.\n\n[^1]: The note.",
+		},
+		{
+			name:     "Hugo",
+			html:     `<p>Hugo<sup id="fnref:1"><a href="#fn:1" class="footnote-ref" role="doc-noteref">1</a></sup> and again<sup id="fnref:2"><a href="#fn:2" class="footnote-ref" role="doc-noteref">2</a></sup>.</p><div class="footnotes" role="doc-endnotes"><hr><ol><li id="fn:1"><p>First&#160;<a href="#fnref:1" class="footnote-backref" role="doc-backlink">&#x21a9;&#xfe0e;</a></p></li><li id="fn:2"><p>Para one.</p><p>Para two&#160;<a href="#fnref:2" class="footnote-backref" role="doc-backlink">&#x21a9;&#xfe0e;</a></p></li></ol></div>`,
+			expected: "Hugo[^1] and again[^2].\n\n[^1]: First\n\n[^2]: Para one.\n\n    Para two",
+		},
+		{
+			name:     "Wikipedia",
+			html:     `<p>Wiki<sup id="cite_ref-1" class="reference"><a href="#cite_note-1">[1]</a></sup> text<sup id="cite_ref-1b" class="reference"><a href="#cite_note-1">[1]</a></sup>.</p><div class="reflist"><ol class="references"><li id="cite_note-1"><span class="mw-cite-backlink">^ <a href="#cite_ref-1">a</a> <a href="#cite_ref-1b">b</a></span> <span class="reference-text">Smith, <i>Book</i>.</span></li></ol></div>`,
+			expected: "Wiki[^1] text[^1].\n\n[^1]: Smith, *Book*.",
+		},
+		{
+			name:     "WordPress",
+			html:     `<p>WP<sup data-fn="abc" class="fn"><a href="#abc" id="abc-link">1</a></sup>.</p><ol class="wp-block-footnotes"><li id="abc">Note text <a href="#abc-link" aria-label="Jump to footnote reference 1">↩︎</a></li></ol>`,
+			expected: "WP[^1].\n\n[^1]: Note text",
+		},
+		{
+			name:     "Numbered by first reference",
+			html:     `<p>B<sup><a href="#n2">x</a></sup> A<sup><a href="#n1">y</a></sup></p><section class="footnotes"><ol><li id="n1">One</li><li id="n2">Two</li><li id="n3">Three</li></ol></section>`,
+			expected: "B[^1] A[^2]\n\n[^1]: Two\n\n[^2]: One\n\n[^3]: Three",
+		},
+		{
+			name:     "Unreferenced list is left alone",
+			html:     `<p>Text</p><div class="references"><ol><li id="r1">Ref</li></ol></div>`,
+			expected: "Text\n\n1. Ref",
+		},
+	}
+
+	options := DefaultOptions()
+	options.Footnotes = true
+	options.StripDocument = STRIP
+
+	for _, test := range tests {
+		result, err := Convert(test.html, options)
+		if err != nil {
+			t.Fatalf("%s: Failed to convert HTML: %v", test.name, err)
+		}
+		if result != test.expected {
+			t.Errorf("%s: Expected %q, got %q", test.name, test.expected, result)
+		}
+	}
+
+	// Test that footnotes are left untouched when disabled or unsupported
+	html := tests[0].html
+	for _, flavor := range []string{GFM, COMMONMARK} {
+		options := DefaultOptions()
+		options.Flavor = flavor
+		options.Footnotes = flavor == COMMONMARK
+		result, err := Convert(html, options)
+		if err != nil {
+			t.Fatalf("Failed to convert HTML: %v", err)
+		}
+		if strings.Contains(result, "[^1]") {
+			t.Errorf("Flavor %s: Expected no footnote conversion, got %q", flavor, result)
+		}
+	}
+
+	// An empty flavor converts footnotes like the default, GFM
+	options = DefaultOptions()
+	options.Footnotes = true
+	options.Flavor = ""
+	result, err := Convert(html, options)
+	if err != nil {
+		t.Fatalf("Failed to convert HTML: %v", err)
+	}
+	if !strings.Contains(result, "[^1]") {
+		t.Errorf("Empty flavor: Expected footnote conversion, got %q", result)
+	}
+}
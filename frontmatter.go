@@ -0,0 +1,344 @@
+package gomarkdownify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"golang.org/x/net/html"
+	"gopkg.in/yaml.v3"
+)
+
+// Metadata holds the document metadata found in the <head> of an HTML document.
+type Metadata struct {
+	// Title is the document title, from <title> or og:title
+	Title string
+	// Description is the document description, from the description or og:description meta tag
+	Description string
+	// Author is the document author, from the author or article:author meta tag
+	Author string
+	// Date is the publication date, from article:published_time or the date meta tag
+	Date string
+	// Modified is the last modification date, from article:modified_time
+	Modified string
+	// Keywords are the comma separated values of the keywords meta tag
+	Keywords []string
+	// Image is the preview image, from og:image
+	Image string
+	// Canonical is the canonical URL, from <link rel="canonical">
+	Canonical string
+	// Language is the lang attribute of the <html> element
+	Language string
+	// OpenGraph holds all og:* properties, keyed without the "og:" prefix
+	OpenGraph map[string]string
+}
+
+// frontMatterKeyOrder lists the keys emitted first, in this order. Other
+// keys (added by FrontMatterCallback) follow in alphabetical order.
+var frontMatterKeyOrder = []string{
+	"title",
+	"description",
+	"author",
+	"date",
+	"lastmod",
+	"keywords",
+	"image",
+	"canonical",
+	"lang",
+}
+
+// extractMetadata collects the document metadata from a parsed document.
+//
+// Parameters:
+//   - doc: The root of the parsed document.
+//
+// Returns:
+//   - The metadata found in the document. Fields that are not present are empty.
+func extractMetadata(doc *html.Node) Metadata {
+	meta := Metadata{OpenGraph: make(map[string]string)}
+	named := make(map[string]string)
+
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch n.Data {
+			case "html":
+				meta.Language = getAttr(n, "lang")
+			case "title":
+				if meta.Title == "" {
+					meta.Title = strings.TrimSpace(reAllWhitespace.ReplaceAllString(textContent(n), " "))
+				}
+			case "meta":
+				content := strings.TrimSpace(getAttr(n, "content"))
+				if property := getAttr(n, "property"); property != "" {
+					if strings.HasPrefix(property, "og:") {
+						meta.OpenGraph[property[3:]] = content
+					}
+					named[strings.ToLower(property)] = content
+				}
+				if name := getAttr(n, "name"); name != "" {
+					named[strings.ToLower(name)] = content
+				}
+			case "link":
+				if strings.EqualFold(getAttr(n, "rel"), "canonical") && meta.Canonical == "" {
+					meta.Canonical = getAttr(n, "href")
+				}
+			case "body":
+				// Metadata only lives in the head
+				return
+			}
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(doc)
+
+	first := func(keys ...string) string {
+		for _, key := range keys {
+			if value := named[key]; value != "" {
+				return value
+			}
+		}
+		return ""
+	}
+
+	if meta.Title == "" {
+		meta.Title = first("og:title", "twitter:title")
+	}
+	meta.Description = first("description", "og:description", "twitter:description")
+	meta.Author = first("author", "article:author")
+	meta.Date = first("article:published_time", "date", "dc.date")
+	meta.Modified = first("article:modified_time", "og:updated_time")
+	meta.Image = first("og:image", "twitter:image")
+
+	for _, keyword := range strings.Split(named["keywords"], ",") {
+		if keyword = strings.TrimSpace(keyword); keyword != "" {
+			meta.Keywords = append(meta.Keywords, keyword)
+		}
+	}
+
+	return meta
+}
+
+// frontMatterFields returns the front matter fields for the metadata,
+// leaving out empty values.
+func (m Metadata) frontMatterFields() map[string]any {
+	fields := make(map[string]any)
+	set := func(key string, value string) {
+		if value != "" {
+			fields[key] = value
+		}
+	}
+
+	set("title", m.Title)
+	set("description", m.Description)
+	set("author", m.Author)
+	set("date", m.Date)
+	set("lastmod", m.Modified)
+	if len(m.Keywords) > 0 {
+		fields["keywords"] = m.Keywords
+	}
+	set("image", m.Image)
+	set("canonical", m.Canonical)
+	set("lang", m.Language)
+
+	return fields
+}
+
+// renderFrontMatter builds the front matter block for a document.
+//
+// The fields are derived from the document metadata and passed through
+// FrontMatterCallback when set. No block is produced when there are no fields.
+//
+// Parameters:
+//   - doc: The root of the parsed document.
+//
+// Returns:
+//   - The serialized front matter followed by a blank line, or an empty string.
+func (c *Converter) renderFrontMatter(doc *html.Node) string {
+	fields := extractMetadata(doc).frontMatterFields()
+	if c.options.FrontMatterCallback != nil {
+		fields = c.options.FrontMatterCallback(fields, doc)
+	}
+	if len(fields) == 0 {
+		return ""
+	}
+
+	keys := orderedFrontMatterKeys(fields)
+	var out strings.Builder
+
+	switch c.options.FrontMatter {
+	case YAML:
+		node, err := yamlNode(fields)
+		if err != nil {
+			c.warn(fmt.Sprintf("front matter cannot be encoded as YAML: %v", err))
+			return ""
+		}
+		out.WriteString("---\n")
+		encoder := yaml.NewEncoder(&out)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(node); err != nil {
+			c.warn(fmt.Sprintf("front matter cannot be encoded as YAML: %v", err))
+			return ""
+		}
+		encoder.Close()
+		out.WriteString("---\n\n")
+	case TOML:
+		out.WriteString("+++\n")
+		writeTOMLTable(&out, fields, keys, "")
+		out.WriteString("+++\n\n")
+	case JSON:
+		out.WriteString("{\n")
+		for i, key := range keys {
+			out.WriteString("  " + jsonValue(key) + ": " + jsonValue(fields[key]))
+			if i < len(keys)-1 {
+				out.WriteString(",")
+			}
+			out.WriteString("\n")
+		}
+		out.WriteString("}\n\n")
+	default:
+		return ""
+	}
+
+	return out.String()
+}
+
+// orderedFrontMatterKeys returns the keys of fields with the well-known keys
+// first and the rest in alphabetical order.
+func orderedFrontMatterKeys(fields map[string]any) []string {
+	keys := make([]string, 0, len(fields))
+	known := make(map[string]bool)
+	for _, key := range frontMatterKeyOrder {
+		known[key] = true
+		if _, ok := fields[key]; ok {
+			keys = append(keys, key)
+		}
+	}
+
+	var rest []string
+	for key := range fields {
+		if !known[key] {
+			rest = append(rest, key)
+		}
+	}
+	sort.Strings(rest)
+
+	return append(keys, rest...)
+}
+
+// yamlNode returns the YAML node of a front matter value. Maps keep the
+// order of orderedFrontMatterKeys, other values are encoded by yaml.v3.
+func yamlNode(value any) (*yaml.Node, error) {
+	fields, ok := value.(map[string]any)
+	if !ok {
+		node := &yaml.Node{}
+		if err := node.Encode(value); err != nil {
+			return nil, err
+		}
+		return node, nil
+	}
+
+	node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for _, key := range orderedFrontMatterKeys(fields) {
+		valueNode, err := yamlNode(fields[key])
+		if err != nil {
+			return nil, err
+		}
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, valueNode)
+	}
+	return node, nil
+}
+
+// writeTOMLTable writes fields as TOML key/value pairs, followed by a table
+// section for each nested map.
+func writeTOMLTable(out *strings.Builder, fields map[string]any, keys []string, prefix string) {
+	var tables []string
+	for _, key := range keys {
+		if _, ok := fields[key].(map[string]any); ok {
+			tables = append(tables, key)
+			continue
+		}
+		if fields[key] == nil {
+			// TOML has no null value
+			continue
+		}
+		out.WriteString(tomlKey(key) + " = " + tomlValue(fields[key]) + "\n")
+	}
+
+	for _, key := range tables {
+		name := prefix + tomlKey(key)
+		table := fields[key].(map[string]any)
+		out.WriteString("\n[" + name + "]\n")
+		writeTOMLTable(out, table, orderedFrontMatterKeys(table), name+".")
+	}
+}
+
+// tomlValue formats a value for TOML: arrays and maps nested in values
+// become arrays and inline tables ("{ a = 1 }"), and null values, which TOML
+// does not have, are left out. Strings, numbers and booleans are written as
+// JSON, which is valid TOML for them.
+func tomlValue(value any) string {
+	if fields, ok := value.(map[string]any); ok {
+		var pairs []string
+		for _, key := range orderedFrontMatterKeys(fields) {
+			if fields[key] != nil {
+				pairs = append(pairs, tomlKey(key)+" = "+tomlValue(fields[key]))
+			}
+		}
+		if len(pairs) == 0 {
+			return "{}"
+		}
+		return "{ " + strings.Join(pairs, ", ") + " }"
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		var items []string
+		for i := 0; i < v.Len(); i++ {
+			if item := v.Index(i).Interface(); item != nil {
+				items = append(items, tomlValue(item))
+			}
+		}
+		return "[" + strings.Join(items, ",") + "]"
+	case reflect.Map:
+		if v.Type().Key().Kind() == reflect.String {
+			fields := make(map[string]any, v.Len())
+			for iter := v.MapRange(); iter.Next(); {
+				fields[iter.Key().String()] = iter.Value().Interface()
+			}
+			return tomlValue(fields)
+		}
+	}
+	return jsonValue(value)
+}
+
+// tomlKey formats a TOML key, quoting it unless it is a bare key.
+func tomlKey(key string) string {
+	for _, r := range key {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-') {
+			return jsonValue(key)
+		}
+	}
+	if key == "" {
+		return `""`
+	}
+	return key
+}
+
+// jsonValue encodes a value as JSON without escaping HTML characters. JSON
+// strings, numbers and booleans are also valid TOML values.
+func jsonValue(value any) string {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return `""`
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
@@ -0,0 +1,166 @@
+package gomarkdownify
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+)
+
+const frontMatterHTML = `<html lang="en"><head>
+<title>My Post: Part 1</title>
+<meta name="description" content="A &quot;great&quot; post">
+<meta name="author" content="Jane Doe">
+<meta property="og:title" content="Ignored">
+<meta property="og:image" content="https://example.com/a.png">
+<meta property="article:published_time" content="2024-01-02T03:04:05Z">
+<meta name="keywords" content="go, html, true">
+<link rel="canonical" href="https://example.com/post">
+</head><body><p>Body</p></body></html>`
+
+// TestExtractMetadata tests the extraction of document metadata from the head
+func TestExtractMetadata(t *testing.T) {
+	doc, err := html.Parse(strings.NewReader(frontMatterHTML))
+	if err != nil {
+		t.Fatalf("Failed to parse HTML: %v", err)
+	}
+
+	meta := extractMetadata(doc)
+	expected := Metadata{
+		Title:       "My Post: Part 1",
+		Description: `A "great" post`,
+		Author:      "Jane Doe",
+		Date:        "2024-01-02T03:04:05Z",
+		Keywords:    []string{"go", "html", "true"},
+		Image:       "https://example.com/a.png",
+		Canonical:   "https://example.com/post",
+		Language:    "en",
+		OpenGraph:   map[string]string{"title": "Ignored", "image": "https://example.com/a.png"},
+	}
+	if !reflect.DeepEqual(meta, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, meta)
+	}
+}
+
+// TestFrontMatter tests the FrontMatter and FrontMatterCallback options
+func TestFrontMatter(t *testing.T) {
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{YAML, `---
+title: 'My Post: Part 1'
+description: A "great" post
+author: Jane Doe
+date: "2024-01-02T03:04:05Z"
+keywords:
+  - go
+  - html
+  - "true"
+image: https://example.com/a.png
+canonical: https://example.com/post
+lang: en
+draft: true
+params:
+  slug: my-post
+  weight: 3
+---
+
+Body`},
+		{TOML, `+++
+title = "My Post: Part 1"
+description = "A \"great\" post"
+author = "Jane Doe"
+date = "2024-01-02T03:04:05Z"
+keywords = ["go","html","true"]
+image = "https://example.com/a.png"
+canonical = "https://example.com/post"
+lang = "en"
+draft = true
+
+[params]
+slug = "my-post"
+weight = 3
++++
+
+Body`},
+		{JSON, `{
+  "title": "My Post: Part 1",
+  "description": "A \"great\" post",
+  "author": "Jane Doe",
+  "date": "2024-01-02T03:04:05Z",
+  "keywords": ["go","html","true"],
+  "image": "https://example.com/a.png",
+  "canonical": "https://example.com/post",
+  "lang": "en",
+  "draft": true,
+  "params": {"slug":"my-post","weight":3}
+}
+
+Body`},
+	}
+
+	for _, test := range tests {
+		options := DefaultOptions()
+		options.FrontMatter = test.format
+		options.StripDocument = STRIP
+		options.FrontMatterCallback = func(fields map[string]any, doc *html.Node) map[string]any {
+			fields["draft"] = true
+			fields["params"] = map[string]any{"weight": 3, "slug": "my-post"}
+			return fields
+		}
+
+		result, err := Convert(frontMatterHTML, options)
+		if err != nil {
+			t.Fatalf("Failed to convert HTML: %v", err)
+		}
+		if result != test.expected {
+			t.Errorf("Format %s: Expected:\n%s\n\nGot:\n%s", test.format, test.expected, result)
+		}
+	}
+
+	// Test that no block is written without metadata
+	options := DefaultOptions()
+	options.FrontMatter = YAML
+	options.StripDocument = STRIP
+	result, err := Convert("<p>Body</p>", options)
+	if err != nil {
+		t.Fatalf("Failed to convert HTML: %v", err)
+	}
+	if result != "Body" {
+		t.Errorf("Expected %q, got %q", "Body", result)
+	}
+
+	// Test that the callback can remove fields
+	options.FrontMatterCallback = func(fields map[string]any, doc *html.Node) map[string]any {
+		return map[string]any{"title": fields["title"]}
+	}
+	result, err = Convert(frontMatterHTML, options)
+	if err != nil {
+		t.Fatalf("Failed to convert HTML: %v", err)
+	}
+	expected := "---\ntitle: 'My Post: Part 1'\n---\n\nBody"
+	if result != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+
+	// Test maps nested in lists
+	options.FrontMatterCallback = func(fields map[string]any, doc *html.Node) map[string]any {
+		return map[string]any{"authors": []any{map[string]any{"name": "Jane Doe", "id": 1}}}
+	}
+	for format, expected := range map[string]string{
+		YAML: "---\nauthors:\n  - id: 1\n    name: Jane Doe\n---\n\nBody",
+		TOML: "+++\nauthors = [{ id = 1, name = \"Jane Doe\" }]\n+++\n\nBody",
+		JSON: "{\n  \"authors\": [{\"id\":1,\"name\":\"Jane Doe\"}]\n}\n\nBody",
+	} {
+		options.FrontMatter = format
+		result, err = Convert(frontMatterHTML, options)
+		if err != nil {
+			t.Fatalf("Failed to convert HTML: %v", err)
+		}
+		if result != expected {
+			t.Errorf("Format %s: Expected %q, got %q", format, expected, result)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package gomarkdownify
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/yuin/goldmark"
+)
+
+// fuzzOptions derives options from the fuzzer's feature bits, so that the
+// optional conversions are exercised together in any combination
+func fuzzOptions(features uint16, bullets string) Options {
+	options := DefaultOptions()
+	options.Bullets = bullets
+	options.Footnotes = features&(1<<0) != 0
+	options.Math = features&(1<<1) != 0
+	options.Callouts = features&(1<<2) != 0
+	options.HeadingIDs = features&(1<<3) != 0
+	options.RewriteAnchorLinks = features&(1<<3) != 0
+	if features&(1<<4) != 0 {
+		options.TOC = TOC_TOP
+	}
+	options.SemanticLineBreaks = features&(1<<5) != 0
+	if features&(1<<6) != 0 {
+		options.Wrap = true
+		options.WrapWidth = 20
+	}
+	if features&(1<<7) != 0 {
+		options.HeadingStyle = ATX
+	}
+	if features&(1<<8) != 0 {
+		options.Details = DETAILS_CALLOUT
+	}
+	options.NormalizeHeadingLevels = features&(1<<9) != 0
+	options.DemoteExtraH1 = features&(1<<9) != 0
+	switch {
+	case features&(1<<10) != 0:
+		options.Flavor = OBSIDIAN
+	case features&(1<<11) != 0:
+		options.Flavor = MKDOCS
+	}
+	if features&(1<<12) != 0 {
+		options.FrontMatter = YAML
+		options.Readability = true
+	}
+	options.EscapeMisc = features&(1<<13) != 0
+	if features&(1<<14) != 0 {
+		options.DeduplicateHeadingsScope = DEDUPLICATE_SECTION
+	}
+	if features&(1<<15) != 0 {
+		options.NewlineStyle = BACKSLASH
+	}
+	return options
+}
+
+// FuzzConvert checks that conversions do not panic, keep valid UTF-8 valid,
+// and produce Markdown a Markdown parser accepts
+func FuzzConvert(f *testing.F) {
+	seeds := []string{
+		"",
+		"<p>Hello <b>world</b></p>",
+		"<ul><li>one<ul><li>two</li></ul></li></ul><ol start=\"0\"><li>three</li></ol>",
+		"<h1>Title<br>Subtitle</h1><h1>Title</h1><h3 id=\"x\">Skip</h3><a href=\"#x\">link</a>",
+		"<table><tr><th>a</th><th colspan=\"2\">b</th></tr><tr><td>1|2</td><td><img src=\"x.png\" alt=\"x\"></td></tr></table>",
+		"<pre><code class=\"language-go\">func main() {}\n</code></pre><p><code>a`b</code></p>",
+		"<blockquote><p>quote</p><blockquote><p>nested</p></blockquote></blockquote>",
+		"<div class=\"admonition warning\"><p class=\"admonition-title\">Careful</p><p>body</p></div>",
+		"<details open><summary>More</summary><p>hidden</p></details>",
+		"<p>x<sup><a href=\"#fn1\" id=\"r1\">1</a></sup></p><ol><li id=\"fn1\">note <a href=\"#r1\">↩</a></li></ol>",
+		"<math><mi>x</mi><mo>=</mo><mfrac><mn>1</mn><mn>2</mn></mfrac></math><script type=\"math/tex\">a^2</script>",
+		"<dl><dt>term</dt><dd>definition</dd></dl><p>日本語の文。 Another sentence.</p>",
+		"<p>*_[]()#+-.!\\ &lt;tag&gt; &amp;</p>",
+	}
+	for i, seed := range seeds {
+		f.Add(seed, uint16(i*4099), "*+-")
+	}
+	pages, _ := filepath.Glob(filepath.Join("testdata", "pages", "*.html"))
+	for _, page := range pages {
+		data, err := os.ReadFile(page)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(string(data), uint16(0xffff), "-")
+	}
+	f.Add("<ul><li>a</li></ul>", uint16(0), "")
+	f.Add("<ul><li>a<ul><li>b</li></ul></li></ul>", uint16(0), "•◦")
+
+	md := goldmark.New()
+	f.Fuzz(func(t *testing.T, input string, features uint16, bullets string) {
+		options := fuzzOptions(features, bullets)
+		options.MaxDepth = 256
+		converter, err := NewConverter(options)
+		if err != nil {
+			// Option sets rejected by Validate, such as empty bullets, cannot be converted
+			t.Skip(err)
+		}
+		result, err := converter.Convert(input)
+		if err != nil {
+			return
+		}
+		if utf8.ValidString(input) && utf8.ValidString(bullets) && !utf8.ValidString(result) {
+			t.Fatalf("Invalid UTF-8 in output %q", result)
+		}
+		var rendered bytes.Buffer
+		if err := md.Convert([]byte(result), &rendered); err != nil {
+			t.Fatalf("Markdown parser rejected output %q: %v", result, err)
+		}
+	})
+}
@@ -2,4 +2,10 @@ module github.com/mrjoshuak/go-markdownify
 
 go 1.24.1
 
-require golang.org/x/net v0.37.0 // indirect
+require (
+	github.com/andybalholm/cascadia v1.3.3
+	github.com/yuin/goldmark v1.8.2
+	golang.org/x/net v0.37.0
+	golang.org/x/text v0.23.0
+	gopkg.in/yaml.v3 v3.0.1
+)
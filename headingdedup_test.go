@@ -0,0 +1,119 @@
+package gomarkdownify
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestDeduplicateHeadingsScope tests the scopes of heading deduplication
+func TestDeduplicateHeadingsScope(t *testing.T) {
+	html := "<h1>API</h1><h2>Get</h2><h3>Examples</h3><h3>Examples</h3>" +
+		"<h2>Put</h2><h3>Examples</h3><h2>Get</h2>"
+
+	tests := []struct {
+		name     string
+		scope    string
+		expected string
+	}{
+		{
+			name:     "Document",
+			scope:    DEDUPLICATE_DOCUMENT,
+			expected: "# API\n\n## Get\n\n### Examples\n\n## Put",
+		},
+		{
+			name:     "Empty scope",
+			scope:    "",
+			expected: "# API\n\n## Get\n\n### Examples\n\n## Put",
+		},
+		{
+			name:     "Section",
+			scope:    DEDUPLICATE_SECTION,
+			expected: "# API\n\n## Get\n\n### Examples\n\n## Put\n\n### Examples",
+		},
+		{
+			name:     "Consecutive",
+			scope:    DEDUPLICATE_CONSECUTIVE,
+			expected: "# API\n\n## Get\n\n### Examples\n\n## Put\n\n### Examples\n\n## Get",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := DefaultOptions()
+			options.HeadingStyle = ATX
+			options.StripDocument = STRIP
+			options.DeduplicateHeadingsScope = tt.scope
+			result, err := Convert(html, options)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+// TestReportDuplicateHeadings tests reporting duplicate headings instead of dropping them
+func TestReportDuplicateHeadings(t *testing.T) {
+	options := DefaultOptions()
+	options.HeadingStyle = ATX
+	options.StripDocument = STRIP
+	options.ReportDuplicateHeadings = true
+
+	converter := newTestConverter(t, options)
+	result, err := converter.ConvertResult("<h2>Notes</h2><p>a</p><h2>Notes</h2><p>b</p>")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "## Notes\n\na\n\n## Notes\n\nb"
+	if result.Markdown != expected {
+		t.Errorf("Expected %q, got %q", expected, result.Markdown)
+	}
+	if len(result.Warnings) != 1 || result.Warnings[0] != `duplicate heading "Notes"` {
+		t.Errorf("Expected a duplicate heading warning, got %q", result.Warnings)
+	}
+	if len(result.Headings) != 2 || result.Headings[1].Slug != "notes-1" {
+		t.Errorf("Expected both headings with unique slugs, got %+v", result.Headings)
+	}
+
+	// Dropped duplicates are reported too
+	options.ReportDuplicateHeadings = false
+	converter = newTestConverter(t, options)
+	result, err = converter.ConvertResult("<h2>Notes</h2><p>a</p><h2>Notes</h2><p>b</p>")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Warnings) != 1 || result.Warnings[0] != `dropped duplicate heading "Notes"` {
+		t.Errorf("Expected a dropped heading warning, got %q", result.Warnings)
+	}
+}
+
+// TestDroppedHeadingLinks tests that a dropped duplicate heading does not
+// report its links and images
+func TestDroppedHeadingLinks(t *testing.T) {
+	options := DefaultOptions()
+	options.HeadingStyle = ATX
+	options.StripDocument = STRIP
+
+	converter := newTestConverter(t, options)
+	html := `<h2><a href="/notes">Notes</a></h2><p><a href="/a">a</a></p><h2><a href="/notes">Notes</a><img src="icon.png" alt=""></h2><p><a href="/b">b</a></p>`
+	result, err := converter.ConvertResult(html)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "## [Notes](/notes)\n\n[a](/a)\n\n[b](/b)"
+	if result.Markdown != expected {
+		t.Errorf("Expected %q, got %q", expected, result.Markdown)
+	}
+	var urls []string
+	for _, link := range result.Links {
+		urls = append(urls, link.URL)
+	}
+	if strings.Join(urls, " ") != "/notes /a /b" {
+		t.Errorf("Expected the links in the output, got %v", urls)
+	}
+	if len(result.Images) != 0 {
+		t.Errorf("Expected no images, got %+v", result.Images)
+	}
+}
@@ -0,0 +1,158 @@
+package gomarkdownify
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestFlavorSlugs tests the heading slugs generated for each flavor
+func TestFlavorSlugs(t *testing.T) {
+	tests := []struct {
+		name     string
+		flavor   string
+		texts    []string
+		expected []string
+	}{
+		{
+			name:     "GitHub",
+			flavor:   GFM,
+			texts:    []string{"1. Getting Started", "Intro", "Intro"},
+			expected: []string{"1-getting-started", "intro", "intro-1"},
+		},
+		{
+			name:     "Pandoc",
+			flavor:   PANDOC,
+			texts:    []string{"1. Getting Started", "Version 2.0", "123"},
+			expected: []string{"getting-started", "version-2.0", "section"},
+		},
+		{
+			name:     "kramdown",
+			flavor:   KRAMDOWN,
+			texts:    []string{"1. Getting Started", "Café Menu"},
+			expected: []string{"getting-started", "caf-menu"},
+		},
+		{
+			name:     "MkDocs",
+			flavor:   MKDOCS,
+			texts:    []string{"Café -- Menu", "Intro", "Intro"},
+			expected: []string{"cafe-menu", "intro", "intro_1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newSlugger(tt.flavor)
+			for i, text := range tt.texts {
+				if result := s.slug(text); result != tt.expected[i] {
+					t.Errorf("slug(%q): Expected %q, got %q", text, tt.expected[i], result)
+				}
+			}
+		})
+	}
+}
+
+// TestHeadingIDs tests keeping heading ids in the output
+func TestHeadingIDs(t *testing.T) {
+	tests := []struct {
+		name     string
+		html     string
+		flavor   string
+		expected string
+	}{
+		{
+			name:     "Attribute block",
+			html:     `<h2 id="setup">Installing</h2>`,
+			flavor:   PANDOC,
+			expected: "## Installing {#setup}",
+		},
+		{
+			name:     "Inline anchor",
+			html:     `<h2 id="setup">Installing</h2>`,
+			flavor:   GFM,
+			expected: "## <a id=\"setup\"></a>Installing",
+		},
+		{
+			name:     "Named anchor inside heading",
+			html:     `<h2><a name="setup"></a>Installing</h2>`,
+			flavor:   KRAMDOWN,
+			expected: "## Installing {#setup}",
+		},
+		{
+			name:     "Heading without id",
+			html:     `<h2>Installing</h2>`,
+			flavor:   PANDOC,
+			expected: "## Installing",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := DefaultOptions()
+			options.HeadingStyle = ATX
+			options.HeadingIDs = true
+			options.Flavor = tt.flavor
+			result, err := Convert(tt.html, options)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+// TestRewriteAnchorLinks tests rewriting same-document links to heading anchors
+func TestRewriteAnchorLinks(t *testing.T) {
+	input := `<p><a href="#sec-2">Usage</a> and <a href="#other">other</a></p>` +
+		`<h2 id="sec-1">Intro</h2><h2 id="sec-2">Usage notes</h2><h2 id="sec-3">Intro</h2>`
+
+	options := DefaultOptions()
+	options.HeadingStyle = ATX
+	options.DeduplicateHeadings = false
+	options.RewriteAnchorLinks = true
+
+	converter := newTestConverter(t, options)
+	result, err := converter.ConvertResult(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "[Usage](#usage-notes) and [other](#other)\n\n## Intro\n\n## Usage notes\n\n## Intro"
+	if result.Markdown != expected {
+		t.Errorf("Expected %q, got %q", expected, result.Markdown)
+	}
+	if slug := result.Headings[2].Slug; slug != "intro-1" {
+		t.Errorf("Expected slug %q, got %q", "intro-1", slug)
+	}
+
+	// With HeadingIDs the links keep pointing at the original ids
+	options.HeadingIDs = true
+	options.Flavor = PANDOC
+	expected = "[Usage](#sec-2) and [other](#other)"
+	if markdown, _ := Convert(input, options); !strings.HasPrefix(markdown, expected) {
+		t.Errorf("Expected prefix %q, got %q", expected, markdown)
+	}
+
+	// Markdown Extra has no automatic ids, links only follow explicit ones
+	options.Flavor = MARKDOWN_EXTRA
+	options.HeadingIDs = false
+	expected = "[Usage](#sec-2) and [other](#other)\n\n## Intro\n\n## Usage notes\n\n## Intro"
+	if markdown, _ := Convert(input, options); markdown != expected {
+		t.Errorf("Expected %q, got %q", expected, markdown)
+	}
+	options.HeadingIDs = true
+	expected = "[Usage](#sec-2) and [other](#other)\n\n## Intro {#sec-1}"
+	if markdown, _ := Convert(input, options); !strings.HasPrefix(markdown, expected) {
+		t.Errorf("Expected prefix %q, got %q", expected, markdown)
+	}
+
+	// Links to an anchor inside a heading point at the heading
+	options = DefaultOptions()
+	options.Flavor = MKDOCS
+	options.RewriteAnchorLinks = true
+	markdown, _ := Convert(`<a href="#old">see</a><h3><a id="old"></a>Café Menu</h3>`, options)
+	expected = "[see](#cafe-menu)"
+	if !strings.HasPrefix(markdown, expected) {
+		t.Errorf("Expected prefix %q, got %q", expected, markdown)
+	}
+}
@@ -0,0 +1,104 @@
+package gomarkdownify
+
+import (
+	"testing"
+)
+
+// TestHeadingLevels tests heading level normalization, demotion and offsets
+func TestHeadingLevels(t *testing.T) {
+	tests := []struct {
+		name     string
+		html     string
+		setup    func(*Options)
+		expected string
+	}{
+		{
+			name:     "Levels unchanged by default",
+			html:     "<h2>A</h2><h4>B</h4>",
+			setup:    func(o *Options) {},
+			expected: "## A\n\n#### B",
+		},
+		{
+			name:     "Normalize closes gaps",
+			html:     "<h2>A</h2><h4>B</h4><h3>C</h3><h2>D</h2><h5>E</h5>",
+			setup:    func(o *Options) { o.NormalizeHeadingLevels = true },
+			expected: "# A\n\n## B\n\n## C\n\n# D\n\n## E",
+		},
+		{
+			name:     "Normalize starts at the first heading",
+			html:     "<h3>A</h3><h2>B</h2><h3>C</h3>",
+			setup:    func(o *Options) { o.NormalizeHeadingLevels = true },
+			expected: "# A\n\n# B\n\n## C",
+		},
+		{
+			name:     "Demote extra H1",
+			html:     "<h1>A</h1><h2>B</h2><h1>C</h1>",
+			setup:    func(o *Options) { o.DemoteExtraH1 = true },
+			expected: "# A\n\n## B\n\n## C",
+		},
+		{
+			name:     "Offset",
+			html:     "<h1>A</h1><h2>B</h2><h6>C</h6>",
+			setup:    func(o *Options) { o.HeadingOffset = 1 },
+			expected: "## A\n\n### B\n\n###### C",
+		},
+		{
+			name:     "Negative offset",
+			html:     "<h2>A</h2><h3>B</h3><h1>C</h1>",
+			setup:    func(o *Options) { o.HeadingOffset = -1 },
+			expected: "# A\n\n## B\n\n# C",
+		},
+		{
+			name: "Normalize, demote and offset",
+			html: "<h2>A</h2><h4>B</h4><h2>C</h2>",
+			setup: func(o *Options) {
+				o.NormalizeHeadingLevels = true
+				o.DemoteExtraH1 = true
+				o.HeadingOffset = 1
+			},
+			expected: "## A\n\n### B\n\n### C",
+		},
+		{
+			name:     "Table cells are not counted",
+			html:     "<table><tr><th><h1>X</h1></th></tr></table><h3>A</h3>",
+			setup:    func(o *Options) { o.NormalizeHeadingLevels = true },
+			expected: "| X |\n| --- |\n\n# A",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := DefaultOptions()
+			options.HeadingStyle = ATX
+			options.StripDocument = STRIP
+			tt.setup(&options)
+			result, err := Convert(tt.html, options)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+// TestHeadingLevelsOutline tests that the outline uses the converted levels
+func TestHeadingLevelsOutline(t *testing.T) {
+	options := DefaultOptions()
+	options.NormalizeHeadingLevels = true
+	converter := newTestConverter(t, options)
+	result, err := converter.ConvertResult("<h2>Guide</h2><h4>Install</h4><h4>Usage</h4>")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Headings) != 1 || result.Headings[0].Level != 1 {
+		t.Fatalf("Expected a single level 1 root heading, got %+v", result.Headings)
+	}
+	if children := result.Headings[0].Children; len(children) != 2 || children[0].Level != 2 {
+		t.Errorf("Expected two level 2 children, got %+v", children)
+	}
+	if result.Title != "Guide" {
+		t.Errorf("Expected title %q, got %q", "Guide", result.Title)
+	}
+}
@@ -0,0 +1,184 @@
+package gomarkdownify
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// headingLineBreak marks a <br> inside a heading until convertH decides how to
+// render it. The HTML parser replaces NUL characters in text, so the marker
+// cannot come from the document.
+const headingLineBreak = "\x00"
+
+// walkHeadings calls fn for each heading that is converted to a Markdown
+// heading, in document order. Headings inside table cells or other headings
+// are converted inline and are not visited.
+//
+// With DETAILS_HEADING, the summary of a <details> element is visited as a
+// heading one level below the preceding heading, before the body; headings
+// in the body follow from the level of the summary. A <details> element
+// without summary text stands for its own heading (see detailsHeading).
+//
+// Parameters:
+//   - doc: The root of the parsed document.
+//   - fn: The function to call with each heading and its HTML level.
+func (c *Converter) walkHeadings(doc *html.Node, fn func(n *html.Node, level int)) {
+	summaries := c.options.Details == DETAILS_HEADING && c.shouldConvertTag("details")
+	previous := 0
+
+	var walk func(n *html.Node, inline bool)
+	walk = func(n *html.Node, inline bool) {
+		var summary *html.Node
+		if n.Type == html.ElementNode {
+			level := headingLevel(n)
+			if level > 0 && !inline {
+				fn(n, level)
+				previous = level
+			}
+			if summaries && n.Data == "details" && !inline {
+				// The body is a section below the summary, closed at the end of
+				// the element
+				defer func(level int) { previous = level }(previous)
+				previous = min(6, previous+1)
+				heading := detailsHeading(n)
+				fn(heading, previous)
+				if heading != n {
+					summary = heading
+				}
+			}
+			inline = inline || level > 0 || n.Data == "td" || n.Data == "th"
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child, inline || child == summary)
+		}
+	}
+	walk(doc, false)
+}
+
+// detailsHeading returns the node standing for the heading of a <details>
+// element converted with DETAILS_HEADING: its <summary>, or the element
+// itself when it has no summary text and is titled "Details".
+func detailsHeading(n *html.Node) *html.Node {
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.ElementNode && child.Data == "summary" {
+			if headingText(child) != "" {
+				return child
+			}
+			break
+		}
+	}
+	return n
+}
+
+// headingText returns the plain text of a heading visited by walkHeadings.
+func headingText(n *html.Node) string {
+	if n.Data == "details" {
+		return "Details"
+	}
+	return strings.TrimSpace(reAllWhitespace.ReplaceAllString(textContent(n), " "))
+}
+
+// headingLevel returns the level of a heading element, or 0 if n is not a heading.
+func headingLevel(n *html.Node) int {
+	if n.Type != html.ElementNode || len(n.Data) != 2 || n.Data[0] != 'h' ||
+		n.Data[1] < '1' || n.Data[1] > '6' {
+		return 0
+	}
+	return int(n.Data[1] - '0')
+}
+
+// computeHeadingLevels determines the Markdown level of every heading in a
+// document according to NormalizeHeadingLevels, DemoteExtraH1 and
+// HeadingOffset, in that order.
+//
+// Normalizing renumbers the headings by their nesting: the first heading
+// becomes level 1, and a heading is one level below the closest preceding
+// heading with a lower HTML level, so skipped levels are closed.
+//
+// Parameters:
+//   - doc: The root of the parsed document.
+//
+// Returns:
+//   - The level of each heading, clamped to 1-6.
+func (c *Converter) computeHeadingLevels(doc *html.Node) map[*html.Node]int {
+	levels := make(map[*html.Node]int)
+	type entry struct{ original, level int }
+	var stack []entry
+	seenH1 := false
+
+	c.walkHeadings(doc, func(n *html.Node, original int) {
+		level := original
+		if c.options.NormalizeHeadingLevels {
+			for len(stack) > 0 && stack[len(stack)-1].original >= original {
+				stack = stack[:len(stack)-1]
+			}
+			level = 1
+			if len(stack) > 0 {
+				level = stack[len(stack)-1].level + 1
+			}
+			stack = append(stack, entry{original, level})
+		}
+
+		if c.options.DemoteExtraH1 && level == 1 {
+			if seenH1 {
+				level = 2
+			}
+			seenH1 = true
+		}
+
+		levels[n] = max(1, min(6, level+c.options.HeadingOffset))
+	})
+
+	return levels
+}
+
+// findDuplicateHeadings finds the headings that repeat an earlier heading
+// within the scope set by DeduplicateHeadingsScope. Headings are compared by
+// their converted level and plain text.
+//
+// Parameters:
+//   - doc: The root of the parsed document.
+//
+// Returns:
+//   - The duplicate headings, or nil if DeduplicateHeadings is disabled.
+func (c *Converter) findDuplicateHeadings(doc *html.Node) map[*html.Node]bool {
+	if !c.options.DeduplicateHeadings {
+		return nil
+	}
+
+	duplicates := make(map[*html.Node]bool)
+	// seen holds the texts of the headings seen so far, by level
+	var seen [7]map[string]bool
+	previous := ""
+
+	c.walkHeadings(doc, func(n *html.Node, level int) {
+		if known, ok := c.state.headingLevels[n]; ok {
+			level = known
+		}
+		level = max(1, min(6, level))
+		text := headingText(n)
+		key := fmt.Sprintf("%d:%s", level, text)
+
+		switch c.options.DeduplicateHeadingsScope {
+		case DEDUPLICATE_CONSECUTIVE:
+			duplicates[n] = key == previous
+		case DEDUPLICATE_SECTION:
+			// A heading closes the sections of all lower-level headings
+			for l := level + 1; l < len(seen); l++ {
+				seen[l] = nil
+			}
+			fallthrough
+		default:
+			if seen[level] == nil {
+				seen[level] = make(map[string]bool)
+			}
+			duplicates[n] = seen[level][text]
+			seen[level][text] = true
+		}
+		previous = key
+	})
+
+	return duplicates
+}
@@ -0,0 +1,217 @@
+package gomarkdownify
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Errors wrapped by a LimitError when a conversion exceeds one of the limits
+// set in Options.
+var (
+	// ErrInputTooLarge is returned when the HTML is larger than MaxInputSize
+	ErrInputTooLarge = errors.New("input too large")
+	// ErrNestingTooDeep is returned when elements are nested deeper than MaxDepth
+	ErrNestingTooDeep = errors.New("nesting too deep")
+	// ErrOutputTooLarge is returned when the Markdown is larger than MaxOutputSize
+	ErrOutputTooLarge = errors.New("output too large")
+)
+
+// LimitError reports a conversion stopped by one of the limits set in Options.
+// Use errors.Is with ErrInputTooLarge, ErrNestingTooDeep or ErrOutputTooLarge
+// to find which limit was exceeded.
+type LimitError struct {
+	// Err is the sentinel error of the exceeded limit
+	Err error
+	// Limit is the value of the exceeded option
+	Limit int
+}
+
+// Error returns the error message.
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("markdownify: %v (limit %d)", e.Err, e.Limit)
+}
+
+// Unwrap returns the sentinel error of the exceeded limit.
+func (e *LimitError) Unwrap() error {
+	return e.Err
+}
+
+// cancelCheckInterval is the number of nodes converted between checks of
+// the context.
+const cancelCheckInterval = 256
+
+// documentDepth returns the deepest element nesting of a document. The tree
+// is walked without recursion so that hostile documents can be measured
+// safely.
+func documentDepth(doc *html.Node) int {
+	depth, maxDepth := 0, 0
+	n := doc
+	for {
+		if n.Type == html.ElementNode {
+			depth++
+			maxDepth = max(maxDepth, depth)
+		}
+		if n.FirstChild != nil {
+			n = n.FirstChild
+			continue
+		}
+		// Leave the node and its finished ancestors until one has a sibling
+		for {
+			if n == doc {
+				return maxDepth
+			}
+			if n.Type == html.ElementNode {
+				depth--
+			}
+			if n.NextSibling != nil {
+				n = n.NextSibling
+				break
+			}
+			n = n.Parent
+		}
+	}
+}
+
+// voidElements are the elements that have no end tag.
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true,
+	"hr": true, "img": true, "input": true, "keygen": true, "link": true,
+	"meta": true, "param": true, "source": true, "track": true, "wbr": true,
+}
+
+// impliedEnd describes the open elements closed by a start tag: the nearest
+// elements in closes, unless one of the elements in scope is found first.
+type impliedEnd struct {
+	closes []string
+	scope  []string
+}
+
+// paragraphEnd is the implied end of an open paragraph.
+var paragraphEnd = impliedEnd{
+	closes: []string{"p"},
+	scope:  []string{"applet", "button", "caption", "marquee", "object", "table", "td", "template", "th"},
+}
+
+// impliedEnds holds the start tags that close open elements.
+var impliedEnds = map[string]impliedEnd{
+	"li":       {closes: []string{"li"}, scope: []string{"menu", "ol", "ul"}},
+	"dt":       {closes: []string{"dd", "dt"}, scope: []string{"dl"}},
+	"dd":       {closes: []string{"dd", "dt"}, scope: []string{"dl"}},
+	"td":       {closes: []string{"td", "th"}, scope: []string{"table", "tr"}},
+	"th":       {closes: []string{"td", "th"}, scope: []string{"table", "tr"}},
+	"tr":       {closes: []string{"td", "th", "tr"}, scope: []string{"table", "tbody", "tfoot", "thead"}},
+	"thead":    {closes: []string{"tbody", "td", "tfoot", "th", "thead", "tr"}, scope: []string{"table"}},
+	"tbody":    {closes: []string{"tbody", "td", "tfoot", "th", "thead", "tr"}, scope: []string{"table"}},
+	"tfoot":    {closes: []string{"tbody", "td", "tfoot", "th", "thead", "tr"}, scope: []string{"table"}},
+	"option":   {closes: []string{"option"}, scope: []string{"datalist", "optgroup", "select"}},
+	"optgroup": {closes: []string{"optgroup", "option"}, scope: []string{"select"}},
+	"p":        paragraphEnd,
+}
+
+// paragraphClosers are the block elements whose start tag closes an open
+// paragraph.
+var paragraphClosers = []string{
+	"address", "article", "aside", "blockquote", "details", "dialog", "div",
+	"dl", "fieldset", "figcaption", "figure", "footer", "form", "h1", "h2",
+	"h3", "h4", "h5", "h6", "header", "hgroup", "hr", "main", "menu", "nav",
+	"ol", "pre", "section", "summary", "table", "ul",
+}
+
+// impliedEndSearch is the number of open elements searched for the
+// elements closed by an implied end. Keeping the search short keeps
+// markupDepth linear, at the cost of missing implied ends of far away
+// elements, which the parser rarely closes on real pages.
+const impliedEndSearch = 32
+
+// markupDepth estimates the deepest element nesting of a document from its
+// tokens, without building the tree. The HTML parser slows down
+// quadratically on deeply nested markup, so MaxDepth is checked on the
+// markup before parsing. End tags implied by the common elements, such as an
+// unclosed <p> or <li>, are taken into account, and the <html> and <body>
+// elements added by the parser are counted, so the estimate matches
+// documentDepth for most documents.
+func markupDepth(htmlContent string) int {
+	var stack []string
+	open := make(map[string]int)
+	maxDepth := 0
+
+	z := html.NewTokenizer(strings.NewReader(htmlContent))
+	for {
+		tt := z.Next()
+		switch tt {
+		case html.ErrorToken:
+			return maxDepth + 2
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, _ := z.TagName()
+			tag := string(name)
+			if tag == "html" || tag == "head" || tag == "body" {
+				continue
+			}
+			if end, ok := impliedEnds[tag]; ok {
+				stack = closeImplied(stack, open, end)
+			}
+			if slices.Contains(paragraphClosers, tag) {
+				stack = closeImplied(stack, open, paragraphEnd)
+			}
+			// The parser adds the tbody of rows outside table sections
+			if tag == "tr" && len(stack) > 0 && stack[len(stack)-1] == "table" {
+				stack = append(stack, "tbody")
+				open["tbody"]++
+			}
+			maxDepth = max(maxDepth, len(stack)+1)
+			if !voidElements[tag] && tt == html.StartTagToken {
+				stack = append(stack, tag)
+				open[tag]++
+			}
+		case html.EndTagToken:
+			name, _ := z.TagName()
+			if open[string(name)] == 0 {
+				continue
+			}
+			for i := len(stack) - 1; i >= 0; i-- {
+				if stack[i] == string(name) {
+					stack = closeFrom(stack, open, i)
+					break
+				}
+			}
+		}
+	}
+}
+
+// closeImplied removes the elements closed by an implied end from the stack
+// of open elements.
+func closeImplied(stack []string, open map[string]int, end impliedEnd) []string {
+	found := false
+	for _, tag := range end.closes {
+		found = found || open[tag] > 0
+	}
+	if !found {
+		return stack
+	}
+
+	cut := -1
+	for i := len(stack) - 1; i >= 0 && i >= len(stack)-impliedEndSearch; i-- {
+		if slices.Contains(end.scope, stack[i]) {
+			break
+		}
+		if slices.Contains(end.closes, stack[i]) {
+			cut = i
+		}
+	}
+	if cut < 0 {
+		return stack
+	}
+	return closeFrom(stack, open, cut)
+}
+
+// closeFrom removes the open elements from index i up.
+func closeFrom(stack []string, open map[string]int, i int) []string {
+	for _, tag := range stack[i:] {
+		open[tag]--
+	}
+	return stack[:i]
+}
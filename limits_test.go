@@ -0,0 +1,204 @@
+package gomarkdownify
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+)
+
+// nestedDivs returns HTML with depth nested div elements around a paragraph
+func nestedDivs(depth int) string {
+	return strings.Repeat("<div>", depth) + "<p>deep</p>" + strings.Repeat("</div>", depth)
+}
+
+// TestConvertLimits tests the input size, nesting depth and output size limits
+func TestConvertLimits(t *testing.T) {
+	tests := []struct {
+		name     string
+		html     string
+		options  func(*Options)
+		expected string
+		err      error
+	}{
+		{
+			name:     "input within limit",
+			html:     "<p>hello world</p>",
+			options:  func(o *Options) { o.MaxInputSize = 18 },
+			expected: "hello world",
+		},
+		{
+			name:    "input too large",
+			html:    "<p>hello world</p>",
+			options: func(o *Options) { o.MaxInputSize = 17 },
+			err:     ErrInputTooLarge,
+		},
+		{
+			// html, body, 3 divs and p
+			name:     "depth within limit",
+			html:     nestedDivs(3),
+			options:  func(o *Options) { o.MaxDepth = 6 },
+			expected: "deep",
+		},
+		{
+			name:    "nesting too deep",
+			html:    nestedDivs(4),
+			options: func(o *Options) { o.MaxDepth = 6 },
+			err:     ErrNestingTooDeep,
+		},
+		{
+			name:    "hostile nesting",
+			html:    nestedDivs(100000),
+			options: func(o *Options) { o.MaxDepth = 512 },
+			err:     ErrNestingTooDeep,
+		},
+		{
+			name:     "output within limit",
+			html:     "<p>hello world</p>",
+			options:  func(o *Options) { o.MaxOutputSize = 11 },
+			expected: "hello world",
+		},
+		{
+			name:    "output too large",
+			html:    strings.Repeat("<p>hello world</p>", 1000),
+			options: func(o *Options) { o.MaxOutputSize = 1000 },
+			err:     ErrOutputTooLarge,
+		},
+		{
+			name:    "output grown by conversion",
+			html:    "<h1>Title</h1>",
+			options: func(o *Options) { o.MaxOutputSize = 8 },
+			err:     ErrOutputTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := DefaultOptions()
+			options.StripDocument = STRIP
+			tt.options(&options)
+
+			result, err := newTestConverter(t, options).ConvertContext(context.Background(), tt.html)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("Expected error %v, got %v", tt.err, err)
+				}
+				var limitErr *LimitError
+				if !errors.As(err, &limitErr) {
+					t.Fatalf("Expected a *LimitError, got %T", err)
+				}
+				if result != "" {
+					t.Errorf("Expected no output, got %q", result)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+// TestLimitErrorMessage tests the message of a LimitError
+func TestLimitErrorMessage(t *testing.T) {
+	err := &LimitError{Err: ErrNestingTooDeep, Limit: 512}
+	if err.Error() != "markdownify: nesting too deep (limit 512)" {
+		t.Errorf("Unexpected message %q", err.Error())
+	}
+}
+
+// cancelAfterContext is a context that reports cancellation after its Err
+// method has been called a number of times
+type cancelAfterContext struct {
+	context.Context
+	calls int
+}
+
+func (c *cancelAfterContext) Err() error {
+	c.calls--
+	if c.calls < 0 {
+		return context.Canceled
+	}
+	return nil
+}
+
+// TestConvertContextCancel tests that conversions stop when the context is cancelled
+func TestConvertContextCancel(t *testing.T) {
+	converter := newTestConverter(t, DefaultOptions())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := converter.ConvertContext(ctx, "<p>hello</p>"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled before converting, got %v", err)
+	}
+
+	// Cancel during the conversion, after the checks around parsing
+	ctx2 := &cancelAfterContext{Context: context.Background(), calls: 2}
+	result, err := converter.ConvertContext(ctx2, strings.Repeat("<p>hello <b>world</b></p>", 1000))
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled during conversion, got %v", err)
+	}
+	if result != "" {
+		t.Errorf("Expected no output, got %q", result)
+	}
+	if _, err := converter.ConvertResult("<p>hello</p>"); err != nil {
+		t.Errorf("Expected the converter to keep working, got %v", err)
+	}
+}
+
+// TestDocumentDepth tests measuring the element nesting of documents
+func TestDocumentDepth(t *testing.T) {
+	tests := []struct {
+		html     string
+		expected int
+	}{
+		{"", 2},
+		{"<p>text</p>", 3},
+		{"<div><p>a</p></div><p>b</p>", 4},
+		{"<ul><li>a<ul><li>b</li></ul></li></ul><p>c</p>", 6},
+		{nestedDivs(1000), 1003},
+	}
+
+	for _, tt := range tests {
+		doc, err := html.Parse(strings.NewReader(tt.html))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if depth := documentDepth(doc); depth != tt.expected {
+			t.Errorf("%.40q: Expected depth %d, got %d", tt.html, tt.expected, depth)
+		}
+	}
+}
+
+// TestMarkupDepth tests estimating the element nesting before parsing
+func TestMarkupDepth(t *testing.T) {
+	tests := []string{
+		"",
+		"<p>text</p>",
+		"<html><body><div><p>a</p></div><p>b</p></body></html>",
+		"<ul><li>a<ul><li>b</li></ul></li></ul><p>c</p>",
+		"<ul><li>a<li>b<li>c</ul>",
+		"<dl><dt>a<dd>b<dt>c<dd>d</dl>",
+		"<table><tr><td>a<td>b<tr><td>c</table>",
+		"<p>one<p>two<div>three</div>",
+		"<div><p>text<br><img src=\"a.png\"><hr></div>",
+		"<select><option>a<option>b</select>",
+		"<div></span><svg><path/></svg></div>",
+		nestedDivs(1000),
+	}
+
+	for _, tt := range tests {
+		doc, err := html.Parse(strings.NewReader(tt))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if depth, expected := markupDepth(tt), documentDepth(doc); depth != expected {
+			t.Errorf("%.40q: Expected depth %d, got %d", tt, expected, depth)
+		}
+	}
+}
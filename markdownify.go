@@ -47,6 +47,9 @@ func Convert(html string, options ...Options) (string, error) {
 	if len(options) > 0 {
 		opts = options[0]
 	}
-	converter := NewConverter(opts)
+	converter, err := NewConverter(opts)
+	if err != nil {
+		return "", err
+	}
 	return converter.Convert(html)
 }
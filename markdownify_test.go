@@ -424,3 +424,13 @@ func TestLineBreaksBasic(t *testing.T) {
 		t.Errorf("Expected %q, got %q", expected, result)
 	}
 }
+
+// newTestConverter creates a converter, failing the test if the options are invalid
+func newTestConverter(tb testing.TB, options Options) *Converter {
+	tb.Helper()
+	converter, err := NewConverter(options)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return converter
+}
@@ -0,0 +1,356 @@
+package gomarkdownify
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// mathRenderingClasses lists the classes of elements that only hold the
+// rendered form of an equation whose source is available elsewhere (for
+// example a MathJax v2 <script type="math/tex"> next to them).
+var mathRenderingClasses = []string{
+	"MathJax",
+	"MathJax_Display",
+	"MathJax_Preview",
+	"MathJax_CHTML",
+	"MathJax_SVG",
+	"MathJax_SVG_Display",
+	"katex-html",
+}
+
+// mathMLOperators maps MathML operator and identifier characters to TeX commands.
+var mathMLOperators = map[string]string{
+	"±": `\pm`, "∓": `\mp`, "×": `\times`, "÷": `\div`, "⋅": `\cdot`, "·": `\cdot`,
+	"∗": `\ast`, "∘": `\circ`, "≤": `\leq`, "≥": `\geq`, "≠": `\neq`, "≈": `\approx`,
+	"≡": `\equiv`, "∼": `\sim`, "≅": `\cong`, "∝": `\propto`, "∞": `\infty`,
+	"∑": `\sum`, "∏": `\prod`, "∫": `\int`, "∮": `\oint`, "∂": `\partial`, "∇": `\nabla`,
+	"√": `\surd`, "∈": `\in`, "∉": `\notin`, "⊂": `\subset`, "⊆": `\subseteq`,
+	"⊃": `\supset`, "⊇": `\supseteq`, "∪": `\cup`, "∩": `\cap`, "∅": `\emptyset`,
+	"∀": `\forall`, "∃": `\exists`, "¬": `\neg`, "∧": `\wedge`, "∨": `\vee`,
+	"→": `\to`, "←": `\leftarrow`, "↔": `\leftrightarrow`, "⇒": `\Rightarrow`,
+	"⇐": `\Leftarrow`, "⇔": `\Leftrightarrow`, "↦": `\mapsto`, "…": `\ldots`,
+	"⋯": `\cdots`, "′": `'`, "″": `''`, "\u2061": "", "\u2062": "", "\u2063": ",",
+	"α": `\alpha`, "β": `\beta`, "γ": `\gamma`, "δ": `\delta`, "ε": `\epsilon`,
+	"ϵ": `\epsilon`, "ζ": `\zeta`, "η": `\eta`, "θ": `\theta`, "ι": `\iota`,
+	"κ": `\kappa`, "λ": `\lambda`, "μ": `\mu`, "ν": `\nu`, "ξ": `\xi`, "π": `\pi`,
+	"ρ": `\rho`, "σ": `\sigma`, "τ": `\tau`, "υ": `\upsilon`, "φ": `\phi`,
+	"ϕ": `\phi`, "χ": `\chi`, "ψ": `\psi`, "ω": `\omega`, "Γ": `\Gamma`,
+	"Δ": `\Delta`, "Θ": `\Theta`, "Λ": `\Lambda`, "Ξ": `\Xi`, "Π": `\Pi`,
+	"Σ": `\Sigma`, "Φ": `\Phi`, "Ψ": `\Psi`, "Ω": `\Omega`,
+}
+
+// mathMLFunctions lists multi-letter identifiers that have a TeX command.
+var mathMLFunctions = map[string]bool{
+	"sin": true, "cos": true, "tan": true, "cot": true, "sec": true, "csc": true,
+	"arcsin": true, "arccos": true, "arctan": true, "sinh": true, "cosh": true,
+	"tanh": true, "log": true, "ln": true, "exp": true, "lim": true, "max": true,
+	"min": true, "sup": true, "inf": true, "det": true, "gcd": true, "deg": true,
+}
+
+// extractMath returns the TeX source of an equation element.
+//
+// The following markup is recognized:
+//   - MathJax v2 <script type="math/tex"> and <script type="math/tex; mode=display">
+//   - MathJax v3 <mjx-container>, using the assistive MathML inside it
+//   - KaTeX <span class="katex"> and <span class="katex-display">, using the
+//     TeX annotation inside the MathML
+//   - <math> elements, using a TeX annotation when present and translating
+//     the MathML otherwise
+//
+// Parameters:
+//   - n: The HTML element node to inspect.
+//
+// Returns:
+//   - tex: The TeX source of the equation.
+//   - display: Whether the equation is displayed as a block.
+//   - ok: Whether n is an equation element.
+func extractMath(n *html.Node) (tex string, display bool, ok bool) {
+	switch {
+	case n.Data == "script":
+		scriptType := strings.ToLower(getAttr(n, "type"))
+		if !strings.HasPrefix(scriptType, "math/tex") {
+			return "", false, false
+		}
+		return strings.TrimSpace(textContent(n)), strings.Contains(scriptType, "mode=display"), true
+	case n.Data == "mjx-container":
+		math := findElement(n, "math")
+		if math == nil {
+			return "", false, false
+		}
+		tex, _, _ := extractMath(math)
+		return tex, getAttr(n, "display") == "true", true
+	case hasClass(n, "katex-display") || hasClass(n, "katex"):
+		annotation := findTeXAnnotation(n)
+		if annotation == nil {
+			return "", false, false
+		}
+		return strings.TrimSpace(textContent(annotation)), hasClass(n, "katex-display"), true
+	case n.Data == "math":
+		display = getAttr(n, "display") == "block" || getAttr(n, "mode") == "display"
+		if annotation := findTeXAnnotation(n); annotation != nil {
+			return strings.TrimSpace(textContent(annotation)), display, true
+		}
+		return strings.TrimSpace(mathMLToTeX(n)), display, true
+	}
+	return "", false, false
+}
+
+// isMathRendering reports whether n holds only the rendered form of an
+// equation and should be dropped when math conversion is enabled.
+func isMathRendering(n *html.Node) bool {
+	for _, class := range mathRenderingClasses {
+		if hasClass(n, class) {
+			return true
+		}
+	}
+	return false
+}
+
+// findTeXAnnotation returns the first <annotation> with a TeX encoding below n.
+func findTeXAnnotation(n *html.Node) *html.Node {
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type != html.ElementNode {
+			continue
+		}
+		if child.Data == "annotation" {
+			encoding := strings.ToLower(getAttr(child, "encoding"))
+			if encoding == "application/x-tex" || encoding == "tex" || encoding == "latex" {
+				return child
+			}
+		}
+		if found := findTeXAnnotation(child); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// findElement returns the first element named tag below n.
+func findElement(n *html.Node, tag string) *html.Node {
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type != html.ElementNode {
+			continue
+		}
+		if child.Data == tag {
+			return child
+		}
+		if found := findElement(child, tag); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// textContent returns the concatenated text of all text nodes below n.
+func textContent(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var text strings.Builder
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		text.WriteString(textContent(child))
+	}
+	return text.String()
+}
+
+// mathMLToTeX translates a MathML element to TeX.
+//
+// Only the presentation elements needed for simple expressions are
+// supported: tokens (mi, mn, mo, mtext), rows, scripts, fractions, roots,
+// over/under scripts, fences and tables. Unknown elements are translated by
+// concatenating their children.
+//
+// Parameters:
+//   - n: The MathML node to translate.
+//
+// Returns:
+//   - The TeX source for the node.
+func mathMLToTeX(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return strings.TrimSpace(n.Data)
+	}
+	if n.Type != html.ElementNode {
+		return ""
+	}
+
+	args := mathMLChildren(n)
+	arg := func(i int) string {
+		if i < len(args) {
+			return mathMLToTeX(args[i])
+		}
+		return ""
+	}
+
+	switch n.Data {
+	case "mi":
+		text := strings.TrimSpace(textContent(n))
+		if tex, ok := mathMLOperators[text]; ok {
+			return tex
+		}
+		if mathMLFunctions[text] {
+			return `\` + text
+		}
+		if len([]rune(text)) > 1 {
+			return `\mathrm{` + text + `}`
+		}
+		return text
+	case "mn":
+		return strings.TrimSpace(textContent(n))
+	case "mo":
+		text := strings.TrimSpace(textContent(n))
+		if tex, ok := mathMLOperators[text]; ok {
+			return tex
+		}
+		switch text {
+		case "{", "}":
+			return `\` + text
+		}
+		return text
+	case "mtext":
+		return `\text{` + textContent(n) + `}`
+	case "mspace":
+		return `\,`
+	case "msup":
+		return texGroup(arg(0)) + "^" + texGroup(arg(1))
+	case "msub":
+		return texGroup(arg(0)) + "_" + texGroup(arg(1))
+	case "msubsup":
+		return texGroup(arg(0)) + "_" + texGroup(arg(1)) + "^" + texGroup(arg(2))
+	case "mfrac":
+		return `\frac{` + arg(0) + `}{` + arg(1) + `}`
+	case "msqrt":
+		return `\sqrt{` + mathMLJoin(args) + `}`
+	case "mroot":
+		return `\sqrt[` + arg(1) + `]{` + arg(0) + `}`
+	case "mover":
+		if getAttr(n, "accent") == "true" {
+			switch arg(1) {
+			case "^":
+				return `\hat{` + arg(0) + `}`
+			case "¯", "‾", "_":
+				return `\bar{` + arg(0) + `}`
+			case "→", `\to`:
+				return `\vec{` + arg(0) + `}`
+			case "~", "˜":
+				return `\tilde{` + arg(0) + `}`
+			case ".", "˙":
+				return `\dot{` + arg(0) + `}`
+			}
+		}
+		return texGroup(arg(0)) + "^" + texGroup(arg(1))
+	case "munder":
+		return texGroup(arg(0)) + "_" + texGroup(arg(1))
+	case "munderover":
+		return texGroup(arg(0)) + "_" + texGroup(arg(1)) + "^" + texGroup(arg(2))
+	case "mfenced":
+		open, close := "(", ")"
+		if hasAttr(n, "open") {
+			open = getAttr(n, "open")
+		}
+		if hasAttr(n, "close") {
+			close = getAttr(n, "close")
+		}
+		parts := make([]string, len(args))
+		for i, child := range args {
+			parts[i] = mathMLToTeX(child)
+		}
+		return `\left` + texDelimiter(open) + strings.Join(parts, ",") + `\right` + texDelimiter(close)
+	case "mtable":
+		rows := make([]string, 0, len(args))
+		for _, row := range args {
+			cells := mathMLChildren(row)
+			parts := make([]string, len(cells))
+			for i, cell := range cells {
+				parts[i] = mathMLToTeX(cell)
+			}
+			rows = append(rows, strings.Join(parts, " & "))
+		}
+		return `\begin{matrix}` + strings.Join(rows, ` \\ `) + `\end{matrix}`
+	case "annotation", "annotation-xml":
+		return ""
+	case "semantics":
+		return arg(0)
+	}
+
+	return mathMLJoin(args)
+}
+
+// mathMLChildren returns the element children of a MathML node.
+func mathMLChildren(n *html.Node) []*html.Node {
+	var children []*html.Node
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.ElementNode {
+			children = append(children, child)
+		}
+	}
+	return children
+}
+
+// mathMLJoin translates and concatenates MathML nodes, separating commands
+// from following letters with a space.
+func mathMLJoin(nodes []*html.Node) string {
+	var tex strings.Builder
+	for _, child := range nodes {
+		part := mathMLToTeX(child)
+		if part == "" {
+			continue
+		}
+		if tex.Len() > 0 && needsTeXSpace(tex.String(), part) {
+			tex.WriteString(" ")
+		}
+		tex.WriteString(part)
+	}
+	return tex.String()
+}
+
+// needsTeXSpace reports whether a space is required between two TeX
+// fragments so that a command name does not run into a following letter.
+func needsTeXSpace(before string, after string) bool {
+	idx := strings.LastIndex(before, `\`)
+	if idx < 0 {
+		return false
+	}
+	command := before[idx+1:]
+	if command == "" || !isASCIILetters(command) {
+		return false
+	}
+	first := after[0]
+	return (first >= 'a' && first <= 'z') || (first >= 'A' && first <= 'Z')
+}
+
+// isASCIILetters reports whether s consists of ASCII letters only.
+func isASCIILetters(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if !((s[i] >= 'a' && s[i] <= 'z') || (s[i] >= 'A' && s[i] <= 'Z')) {
+			return false
+		}
+	}
+	return true
+}
+
+// texGroup wraps a TeX fragment in braces unless it is a single character
+// or a single command.
+func texGroup(tex string) string {
+	if len([]rune(tex)) == 1 || (len(tex) > 1 && tex[0] == '\\' && isASCIILetters(tex[1:])) {
+		return tex
+	}
+	return "{" + tex + "}"
+}
+
+// texDelimiter converts a fence character to a TeX delimiter for \left and \right.
+func texDelimiter(delim string) string {
+	switch delim {
+	case "":
+		return "."
+	case "{", "}":
+		return `\` + delim
+	case "‖":
+		return `\|`
+	case "⟨":
+		return `\langle`
+	case "⟩":
+		return `\rangle`
+	}
+	return delim
+}
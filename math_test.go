@@ -0,0 +1,101 @@
+package gomarkdownify
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestMath tests the conversion of MathJax, KaTeX and MathML equations
+func TestMath(t *testing.T) {
+	tests := []struct {
+		name     string
+		html     string
+		expected string
+	}{
+		{
+			name:     "MathJax scripts",
+			html:     `<p>Euler: <span class="MathJax_Preview">e</span><span class="MathJax">rendered</span><script type="math/tex">e^{i\pi}+1=0</script> done.</p><script type="math/tex; mode=display">\int_0^1 x\,dx</script>`,
+			expected: "Euler: $e^{i\\pi}+1=0$ done.\n\n$$\n\\int_0^1 x\\,dx\n$$",
+		},
+		{
+			name:     "KaTeX",
+			html:     `<p>KaTeX <span class="katex"><span class="katex-mathml"><math><semantics><mrow><msup><mi>x</mi><mn>2</mn></msup></mrow><annotation encoding="application/x-tex">x^2</annotation></semantics></math></span><span class="katex-html" aria-hidden="true">x2</span></span> inline.</p><span class="katex-display"><span class="katex"><span class="katex-mathml"><math><semantics><mi>y</mi><annotation encoding="application/x-tex">\frac{a}{b}</annotation></semantics></math></span><span class="katex-html">junk</span></span></span>`,
+			expected: "KaTeX $x^2$ inline.\n\n$$\n\\frac{a}{b}\n$$",
+		},
+		{
+			name:     "MathML",
+			html:     `<p>Roots: <math><mfrac><mrow><mo>-</mo><mi>b</mi><mo>±</mo><msqrt><msup><mi>b</mi><mn>2</mn></msup><mo>-</mo><mn>4</mn><mi>a</mi><mi>c</mi></msqrt></mrow><mrow><mn>2</mn><mi>a</mi></mrow></mfrac></math></p><math display="block"><msubsup><mo>∑</mo><mrow><mi>i</mi><mo>=</mo><mn>1</mn></mrow><mi>n</mi></msubsup><mi>sin</mi><mi>α</mi></math>`,
+			expected: "Roots: $\\frac{-b\\pm\\sqrt{b^2-4ac}}{2a}$\n\n$$\n\\sum_{i=1}^n\\sin\\alpha\n$$",
+		},
+		{
+			name:     "MathJax v3",
+			html:     `<mjx-container class="MathJax" display="true"><mjx-math>junk</mjx-math><mjx-assistive-mml><math display="block"><mi>π</mi><mi>r</mi></math></mjx-assistive-mml></mjx-container>`,
+			expected: "$$\n\\pi r\n$$",
+		},
+		{
+			name:     "Display math in a table cell",
+			html:     `<table><tr><th>Formula</th></tr><tr><td><math display="block"><mi>x</mi></math></td></tr></table>`,
+			expected: "| Formula |\n| --- |\n| $x$ |",
+		},
+	}
+
+	options := DefaultOptions()
+	options.Math = true
+	options.StripDocument = STRIP
+
+	for _, test := range tests {
+		result, err := Convert(test.html, options)
+		if err != nil {
+			t.Fatalf("%s: Failed to convert HTML: %v", test.name, err)
+		}
+		if result != test.expected {
+			t.Errorf("%s: Expected %q, got %q", test.name, test.expected, result)
+		}
+	}
+
+	// Test custom delimiters
+	options.InlineMathDelimiters = [2]string{`\(`, `\)`}
+	options.DisplayMathDelimiters = [2]string{`\[`, `\]`}
+	result, err := Convert(`<p>A <script type="math/tex">x</script></p><script type="math/tex; mode=display">y</script>`, options)
+	if err != nil {
+		t.Fatalf("Failed to convert HTML: %v", err)
+	}
+	expected := "A \\(x\\)\n\n\\[\ny\n\\]"
+	if result != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+
+	// Test that math scripts are dropped when disabled
+	result, err = Convert(`<p>A <script type="math/tex">x</script></p>`)
+	if err != nil {
+		t.Fatalf("Failed to convert HTML: %v", err)
+	}
+	if strings.Contains(result, "$") {
+		t.Errorf("Expected no math conversion, got %q", result)
+	}
+}
+
+// TestMathMLToTeX tests the MathML to TeX translation of individual elements
+func TestMathMLToTeX(t *testing.T) {
+	tests := []struct {
+		mathML   string
+		expected string
+	}{
+		{`<msub><mi>x</mi><mi>i</mi></msub>`, "x_i"},
+		{`<mroot><mi>x</mi><mn>3</mn></mroot>`, `\sqrt[3]{x}`},
+		{`<mover accent="true"><mi>v</mi><mo>→</mo></mover>`, `\vec{v}`},
+		{`<mfenced><mi>a</mi><mi>b</mi></mfenced>`, `\left(a,b\right)`},
+		{`<mtable><mtr><mtd><mn>1</mn></mtd><mtd><mn>0</mn></mtd></mtr><mtr><mtd><mn>0</mn></mtd><mtd><mn>1</mn></mtd></mtr></mtable>`, `\begin{matrix}1 & 0 \\ 0 & 1\end{matrix}`},
+		{`<mi>ln</mi><mi>x</mi>`, `\ln x`},
+		{`<mtext>if</mtext>`, `\text{if}`},
+	}
+
+	for _, test := range tests {
+		html := "<math>" + test.mathML + "</math>"
+		node := parseHTMLAndGetNode(t, html, "math")
+		result := mathMLToTeX(node)
+		if result != test.expected {
+			t.Errorf("mathMLToTeX(%q): Expected %q, got %q", test.mathML, test.expected, result)
+		}
+	}
+}
@@ -0,0 +1,365 @@
+package gomarkdownify
+
+import (
+	"slices"
+
+	"golang.org/x/net/html"
+)
+
+// Option configures a Converter created by NewConverter. An Options struct
+// is an Option that replaces all options, the With functions change a single
+// option, and WithPreset applies the preset of a flavor.
+type Option interface {
+	apply(options *Options) error
+}
+
+// apply replaces the options with o.
+func (o Options) apply(options *Options) error {
+	*options = o
+	return nil
+}
+
+// OptionFunc is an Option that changes the options with a function. It
+// allows settings that have no With function, or that depend on each other.
+//
+// Example:
+//
+//	converter, err := gomarkdownify.NewConverter(
+//	    gomarkdownify.WithFlavor(gomarkdownify.OBSIDIAN),
+//	    gomarkdownify.OptionFunc(func(o *gomarkdownify.Options) {
+//	        o.Math = o.Flavor == gomarkdownify.OBSIDIAN
+//	    }),
+//	)
+type OptionFunc func(options *Options)
+
+// apply calls the function with the options.
+func (f OptionFunc) apply(options *Options) error {
+	f(options)
+	return nil
+}
+
+// Preset returns the options suited to a Markdown flavor: the default
+// options with the flavor set, ATX headings, "-" bullets and the optional
+// conversions the flavor has a syntax for, such as footnotes, callouts, math
+// and heading ids.
+//
+// Parameters:
+//   - flavor: One of COMMONMARK, GFM, PANDOC, MARKDOWN_EXTRA, KRAMDOWN, MKDOCS or OBSIDIAN.
+//
+// Returns:
+//   - The preset options, or an *OptionError for an unknown flavor.
+func Preset(flavor string) (Options, error) {
+	options := DefaultOptions()
+	options.Flavor = flavor
+	options.HeadingStyle = ATX
+	options.Bullets = "-"
+
+	switch flavor {
+	case COMMONMARK:
+	case GFM:
+		options.Footnotes = true
+		options.Callouts = true
+	case PANDOC:
+		options.Footnotes = true
+		options.Math = true
+		options.HeadingIDs = true
+	case MARKDOWN_EXTRA:
+		options.Footnotes = true
+		options.HeadingIDs = true
+	case KRAMDOWN:
+		options.Footnotes = true
+		options.Math = true
+		options.InlineMathDelimiters = [2]string{"$$", "$$"}
+		options.HeadingIDs = true
+	case MKDOCS:
+		options.Footnotes = true
+		options.Callouts = true
+		options.HeadingIDs = true
+	case OBSIDIAN:
+		options.Footnotes = true
+		options.Callouts = true
+		options.Math = true
+	default:
+		return Options{}, &OptionError{
+			Option: "Preset",
+			Value:  flavor,
+			Reason: "must be one of " + quoteList([]string{COMMONMARK, GFM, PANDOC, MARKDOWN_EXTRA, KRAMDOWN, MKDOCS, OBSIDIAN}),
+		}
+	}
+	return options, nil
+}
+
+// presetOption is the Option returned by WithPreset.
+type presetOption string
+
+// apply replaces the options with the preset.
+func (p presetOption) apply(options *Options) error {
+	preset, err := Preset(string(p))
+	if err != nil {
+		return err
+	}
+	*options = preset
+	return nil
+}
+
+// WithPreset replaces all options with the preset of a flavor, see Preset.
+// Options given after it change the preset.
+func WithPreset(flavor string) Option {
+	return presetOption(flavor)
+}
+
+// WithAutolinks sets Options.Autolinks.
+func WithAutolinks(enabled bool) Option {
+	return OptionFunc(func(o *Options) { o.Autolinks = enabled })
+}
+
+// WithBullets sets the bullet characters of unordered lists, see Options.Bullets.
+func WithBullets(bullets string) Option {
+	return OptionFunc(func(o *Options) { o.Bullets = bullets })
+}
+
+// WithCallouts sets Options.Callouts.
+func WithCallouts(enabled bool) Option {
+	return OptionFunc(func(o *Options) { o.Callouts = enabled })
+}
+
+// WithCalloutRules sets the rules recognizing callouts, see Options.CalloutRules.
+func WithCalloutRules(rules ...CalloutRule) Option {
+	rules = slices.Clone(rules)
+	return OptionFunc(func(o *Options) { o.CalloutRules = rules })
+}
+
+// WithCodeLanguage sets the default language of code blocks, see Options.CodeLanguage.
+func WithCodeLanguage(language string) Option {
+	return OptionFunc(func(o *Options) { o.CodeLanguage = language })
+}
+
+// WithCodeLanguageCallback sets Options.CodeLanguageCallback.
+func WithCodeLanguageCallback(callback func(n *html.Node) string) Option {
+	return OptionFunc(func(o *Options) { o.CodeLanguageCallback = callback })
+}
+
+// WithConvert converts only the given tags, see Options.Convert.
+func WithConvert(tags ...string) Option {
+	tags = slices.Clone(tags)
+	if tags == nil {
+		tags = []string{}
+	}
+	return OptionFunc(func(o *Options) { o.Convert = tags })
+}
+
+// WithDefaultTitle sets Options.DefaultTitle.
+func WithDefaultTitle(enabled bool) Option {
+	return OptionFunc(func(o *Options) { o.DefaultTitle = enabled })
+}
+
+// WithDetails sets how <details> sections are converted, see Options.Details.
+func WithDetails(style string) Option {
+	return OptionFunc(func(o *Options) { o.Details = style })
+}
+
+// WithStripLinkTitles sets Options.StripLinkTitles.
+func WithStripLinkTitles(enabled bool) Option {
+	return OptionFunc(func(o *Options) { o.StripLinkTitles = enabled })
+}
+
+// WithEscapeAsterisks sets Options.EscapeAsterisks.
+func WithEscapeAsterisks(enabled bool) Option {
+	return OptionFunc(func(o *Options) { o.EscapeAsterisks = enabled })
+}
+
+// WithEscapeUnderscores sets Options.EscapeUnderscores.
+func WithEscapeUnderscores(enabled bool) Option {
+	return OptionFunc(func(o *Options) { o.EscapeUnderscores = enabled })
+}
+
+// WithEscapeMisc sets Options.EscapeMisc.
+func WithEscapeMisc(enabled bool) Option {
+	return OptionFunc(func(o *Options) { o.EscapeMisc = enabled })
+}
+
+// WithExclude removes the elements matching CSS selectors, see Options.Exclude.
+func WithExclude(selectors ...string) Option {
+	selectors = slices.Clone(selectors)
+	return OptionFunc(func(o *Options) { o.Exclude = selectors })
+}
+
+// WithFlavor sets the Markdown dialect of the output, see Options.Flavor.
+// Unlike WithPreset, it changes no other option.
+func WithFlavor(flavor string) Option {
+	return OptionFunc(func(o *Options) { o.Flavor = flavor })
+}
+
+// WithFootnotes sets Options.Footnotes.
+func WithFootnotes(enabled bool) Option {
+	return OptionFunc(func(o *Options) { o.Footnotes = enabled })
+}
+
+// WithFrontMatter sets the front matter format, see Options.FrontMatter.
+func WithFrontMatter(format string) Option {
+	return OptionFunc(func(o *Options) { o.FrontMatter = format })
+}
+
+// WithFrontMatterCallback sets Options.FrontMatterCallback.
+func WithFrontMatterCallback(callback func(fields map[string]any, doc *html.Node) map[string]any) Option {
+	return OptionFunc(func(o *Options) { o.FrontMatterCallback = callback })
+}
+
+// WithHeadingIDs sets Options.HeadingIDs.
+func WithHeadingIDs(enabled bool) Option {
+	return OptionFunc(func(o *Options) { o.HeadingIDs = enabled })
+}
+
+// WithHeadingStyle sets the style of headings, see Options.HeadingStyle.
+func WithHeadingStyle(style string) Option {
+	return OptionFunc(func(o *Options) { o.HeadingStyle = style })
+}
+
+// WithHeadingOffset sets Options.HeadingOffset.
+func WithHeadingOffset(offset int) Option {
+	return OptionFunc(func(o *Options) { o.HeadingOffset = offset })
+}
+
+// WithNormalizeHeadingLevels sets Options.NormalizeHeadingLevels.
+func WithNormalizeHeadingLevels(enabled bool) Option {
+	return OptionFunc(func(o *Options) { o.NormalizeHeadingLevels = enabled })
+}
+
+// WithDemoteExtraH1 sets Options.DemoteExtraH1.
+func WithDemoteExtraH1(enabled bool) Option {
+	return OptionFunc(func(o *Options) { o.DemoteExtraH1 = enabled })
+}
+
+// WithInclude converts only the elements matching CSS selectors, see Options.Include.
+func WithInclude(selectors ...string) Option {
+	selectors = slices.Clone(selectors)
+	return OptionFunc(func(o *Options) { o.Include = selectors })
+}
+
+// WithKeepInlineImagesIn sets the tags keeping inline images, see Options.KeepInlineImagesIn.
+func WithKeepInlineImagesIn(tags ...string) Option {
+	tags = slices.Clone(tags)
+	return OptionFunc(func(o *Options) { o.KeepInlineImagesIn = tags })
+}
+
+// WithMath sets Options.Math.
+func WithMath(enabled bool) Option {
+	return OptionFunc(func(o *Options) { o.Math = enabled })
+}
+
+// WithMathDelimiters sets Options.InlineMathDelimiters and Options.DisplayMathDelimiters.
+func WithMathDelimiters(inline [2]string, display [2]string) Option {
+	return OptionFunc(func(o *Options) {
+		o.InlineMathDelimiters = inline
+		o.DisplayMathDelimiters = display
+	})
+}
+
+// WithMaxInputSize sets Options.MaxInputSize.
+func WithMaxInputSize(size int) Option {
+	return OptionFunc(func(o *Options) { o.MaxInputSize = size })
+}
+
+// WithMaxDepth sets Options.MaxDepth.
+func WithMaxDepth(depth int) Option {
+	return OptionFunc(func(o *Options) { o.MaxDepth = depth })
+}
+
+// WithMaxOutputSize sets Options.MaxOutputSize.
+func WithMaxOutputSize(size int) Option {
+	return OptionFunc(func(o *Options) { o.MaxOutputSize = size })
+}
+
+// WithNewlineStyle sets the style of line breaks, see Options.NewlineStyle.
+func WithNewlineStyle(style string) Option {
+	return OptionFunc(func(o *Options) { o.NewlineStyle = style })
+}
+
+// WithNormalizeNewlines sets Options.NormalizeNewlines.
+func WithNormalizeNewlines(enabled bool) Option {
+	return OptionFunc(func(o *Options) { o.NormalizeNewlines = enabled })
+}
+
+// WithSemanticLineBreaks sets Options.SemanticLineBreaks and Options.SemanticLineBreakClauses.
+func WithSemanticLineBreaks(enabled bool, clauses bool) Option {
+	return OptionFunc(func(o *Options) {
+		o.SemanticLineBreaks = enabled
+		o.SemanticLineBreakClauses = clauses
+	})
+}
+
+// WithReadability sets Options.Readability.
+func WithReadability(enabled bool) Option {
+	return OptionFunc(func(o *Options) { o.Readability = enabled })
+}
+
+// WithRewriteAnchorLinks sets Options.RewriteAnchorLinks.
+func WithRewriteAnchorLinks(enabled bool) Option {
+	return OptionFunc(func(o *Options) { o.RewriteAnchorLinks = enabled })
+}
+
+// WithStrip strips the given tags, keeping their content, see Options.Strip.
+func WithStrip(tags ...string) Option {
+	tags = slices.Clone(tags)
+	return OptionFunc(func(o *Options) { o.Strip = tags })
+}
+
+// WithStripDocument sets how document-level newlines are stripped, see Options.StripDocument.
+func WithStripDocument(style string) Option {
+	return OptionFunc(func(o *Options) { o.StripDocument = style })
+}
+
+// WithStrongEmSymbol sets the symbol of strong and emphasis, see Options.StrongEmSymbol.
+func WithStrongEmSymbol(symbol string) Option {
+	return OptionFunc(func(o *Options) { o.StrongEmSymbol = symbol })
+}
+
+// WithSubSymbol sets Options.SubSymbol.
+func WithSubSymbol(symbol string) Option {
+	return OptionFunc(func(o *Options) { o.SubSymbol = symbol })
+}
+
+// WithSupSymbol sets Options.SupSymbol.
+func WithSupSymbol(symbol string) Option {
+	return OptionFunc(func(o *Options) { o.SupSymbol = symbol })
+}
+
+// WithTableInferHeader sets Options.TableInferHeader.
+func WithTableInferHeader(enabled bool) Option {
+	return OptionFunc(func(o *Options) { o.TableInferHeader = enabled })
+}
+
+// WithDeduplicateHeadings sets Options.DeduplicateHeadings.
+func WithDeduplicateHeadings(enabled bool) Option {
+	return OptionFunc(func(o *Options) { o.DeduplicateHeadings = enabled })
+}
+
+// WithDeduplicateHeadingsScope sets Options.DeduplicateHeadingsScope.
+func WithDeduplicateHeadingsScope(scope string) Option {
+	return OptionFunc(func(o *Options) { o.DeduplicateHeadingsScope = scope })
+}
+
+// WithReportDuplicateHeadings sets Options.ReportDuplicateHeadings.
+func WithReportDuplicateHeadings(enabled bool) Option {
+	return OptionFunc(func(o *Options) { o.ReportDuplicateHeadings = enabled })
+}
+
+// WithTOC inserts a table of contents listing the headings from minLevel to
+// maxLevel, see Options.TOC. An empty position removes the table of contents.
+func WithTOC(position string, minLevel int, maxLevel int) Option {
+	return OptionFunc(func(o *Options) {
+		o.TOC = position
+		o.TOCMinLevel = minLevel
+		o.TOCMaxLevel = maxLevel
+	})
+}
+
+// WithWrap wraps text at width, or disables wrapping when width is 0.
+func WithWrap(width int) Option {
+	return OptionFunc(func(o *Options) {
+		o.Wrap = width != 0
+		if width != 0 {
+			o.WrapWidth = width
+		}
+	})
+}
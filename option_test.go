@@ -0,0 +1,171 @@
+package gomarkdownify
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"golang.org/x/net/html"
+	"gopkg.in/yaml.v3"
+)
+
+// TestNewConverterOptions tests configuring converters with functional options
+func TestNewConverterOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  []Option
+		html     string
+		expected string
+	}{
+		{
+			name:     "defaults",
+			html:     "<h2>Title</h2><ul><li>a</li></ul>",
+			expected: "Title\n-----\n\n* a\n",
+		},
+		{
+			name:     "with functions",
+			options:  []Option{WithHeadingStyle(ATX), WithBullets("-"), WithStripDocument(STRIP)},
+			html:     "<h2>Title</h2><ul><li>a</li></ul>",
+			expected: "## Title\n\n- a",
+		},
+		{
+			name:     "struct replaces earlier options",
+			options:  []Option{WithHeadingStyle(ATX), DefaultOptions(), WithStripDocument(STRIP)},
+			html:     "<h2>Title</h2>",
+			expected: "Title\n-----",
+		},
+		{
+			name:     "preset with overrides",
+			options:  []Option{WithPreset(GFM), WithBullets("+"), WithStripDocument(STRIP)},
+			html:     "<h2>Title</h2><ul><li>a</li></ul>",
+			expected: "## Title\n\n+ a",
+		},
+		{
+			name: "option func",
+			options: []Option{WithStripDocument(STRIP), OptionFunc(func(o *Options) {
+				o.Strip = []string{"a"}
+			})},
+			html:     "<a href=\"/x\">link</a>",
+			expected: "link",
+		},
+		{
+			name:     "wrap",
+			options:  []Option{WithWrap(10), WithStripDocument(STRIP)},
+			html:     "<p>one two three four</p>",
+			expected: "one two\nthree four",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			converter, err := NewConverter(tt.options...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			result, err := converter.Convert(tt.html)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+// TestNewConverterOptionErrors tests reporting invalid functional options
+func TestNewConverterOptionErrors(t *testing.T) {
+	var optionErr *OptionError
+	if _, err := NewConverter(WithHeadingStyle("atxx")); !errors.As(err, &optionErr) || optionErr.Option != "HeadingStyle" {
+		t.Errorf("Expected a HeadingStyle error, got %v", err)
+	}
+	if _, err := NewConverter(WithPreset("github")); !errors.As(err, &optionErr) || optionErr.Option != "Preset" {
+		t.Errorf("Expected a Preset error, got %v", err)
+	}
+	// Options are validated after all are applied
+	if _, err := NewConverter(WithBullets(""), WithBullets("*")); err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+}
+
+// TestPresets tests that every flavor has a valid preset
+func TestPresets(t *testing.T) {
+	for _, flavor := range []string{COMMONMARK, GFM, PANDOC, MARKDOWN_EXTRA, KRAMDOWN, MKDOCS, OBSIDIAN} {
+		options, err := Preset(flavor)
+		if err != nil {
+			t.Fatalf("%s: %v", flavor, err)
+		}
+		if options.Flavor != flavor || options.HeadingStyle != ATX {
+			t.Errorf("%s: unexpected preset %+v", flavor, options)
+		}
+		if err := options.Validate(); err != nil {
+			t.Errorf("%s: %v", flavor, err)
+		}
+	}
+
+	options, _ := Preset(OBSIDIAN)
+	if !options.Callouts || !options.Math || !options.Footnotes {
+		t.Errorf("Expected callouts, math and footnotes in the Obsidian preset")
+	}
+}
+
+// TestWithSlicesCopied tests that options do not share the caller's slices
+func TestWithSlicesCopied(t *testing.T) {
+	tags := []string{"a", "b"}
+	converter, err := NewConverter(WithStrip(tags...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tags[0] = "p"
+	if converter.options.Strip[0] != "a" {
+		t.Errorf("Expected a copy of the tags, got %v", converter.options.Strip)
+	}
+}
+
+// TestOptionsMarshal tests storing options as JSON and YAML
+func TestOptionsMarshal(t *testing.T) {
+	options, err := Preset(KRAMDOWN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	options.CalloutRules = []CalloutRule{{Class: "note", Type: "NOTE"}}
+	options.Strip = []string{"span"}
+	options.KeepInlineImagesIn = []string{"td"}
+	// Callbacks are not stored
+	options.CodeLanguageCallback = func(n *html.Node) string { return "go" }
+
+	data, err := json.Marshal(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := DefaultOptions()
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	options.CodeLanguageCallback = nil
+	if !reflect.DeepEqual(decoded, options) {
+		t.Errorf("JSON round trip changed options:\n%+v\n%+v", options, decoded)
+	}
+
+	data, err = yaml.Marshal(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded = DefaultOptions()
+	if err := yaml.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, options) {
+		t.Errorf("YAML round trip changed options:\n%+v\n%+v", options, decoded)
+	}
+
+	// Enums are stored as their names
+	var fields map[string]any
+	if err := yaml.Unmarshal(data, &fields); err != nil {
+		t.Fatal(err)
+	}
+	if fields["HeadingStyle"] != ATX || fields["Flavor"] != KRAMDOWN {
+		t.Errorf("Expected enum names, got %v and %v", fields["HeadingStyle"], fields["Flavor"])
+	}
+}
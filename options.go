@@ -1,6 +1,12 @@
 package gomarkdownify
 
 import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/andybalholm/cascadia"
 	"golang.org/x/net/html"
 )
 
@@ -11,129 +17,409 @@ type Options struct {
 	// Autolinks determines whether to use <url> syntax for URLs that match their link text.
 	// When true, links like <a href="https://example.com">https://example.com</a> will be
 	// converted to <https://example.com> instead of [https://example.com](https://example.com).
-	Autolinks bool
+	Autolinks bool `yaml:"Autolinks"`
 
 	// Bullets specifies the characters to use for unordered list items.
 	// The characters are used in the order provided, with different levels of nesting
 	// using different characters. For example, "*+-" would use * for the first level,
 	// + for the second level, and - for the third level.
-	Bullets string
+	Bullets string `yaml:"Bullets"`
+
+	// Callouts determines whether to convert callouts (admonitions and alerts such as
+	// <div class="admonition warning"> or <div class="alert alert-info">) to the syntax
+	// of the chosen Flavor: GitHub alerts ("> [!WARNING]") for GFM, callouts
+	// ("> [!warning] Title") for OBSIDIAN, admonitions ("!!! warning "Title"") for
+	// MKDOCS, and a blockquote starting with the title in bold for other flavors.
+	Callouts bool `yaml:"Callouts"`
+
+	// CalloutRules lists the rules used to recognize callouts and their types.
+	// If nil, DefaultCalloutRules is used. A nil list is omitted from YAML.
+	CalloutRules []CalloutRule `yaml:"CalloutRules,omitempty"`
 
 	// CodeLanguage specifies the default language for code blocks.
 	// This is used when a code block doesn't have a language specified.
-	CodeLanguage string
+	CodeLanguage string `yaml:"CodeLanguage"`
 
 	// CodeLanguageCallback is a function that determines the language for a code block
 	// based on the HTML node. This allows for custom logic to extract language information
 	// from class attributes or other node properties.
-	CodeLanguageCallback func(n *html.Node) string
+	CodeLanguageCallback func(n *html.Node) string `json:"-" yaml:"-"`
 
 	// Convert is a list of tags to convert. If nil, all supported tags are converted.
-	// This can be used to limit which HTML tags are processed.
-	Convert []string
+	// This can be used to limit which HTML tags are processed. A nil list is
+	// omitted from YAML, where it would otherwise be written as an empty list.
+	Convert []string `yaml:"Convert,omitempty"`
 
 	// DefaultTitle determines whether to use the href as the title for links
 	// when no title attribute is provided.
-	DefaultTitle bool
-	
+	DefaultTitle bool `yaml:"DefaultTitle"`
+
+	// Details specifies how <details> collapsible sections are converted.
+	// Valid values are DETAILS_HTML (keep <details> and <summary> as HTML around the body
+	// converted to Markdown, as rendered by GitHub), DETAILS_CALLOUT (a callout titled with
+	// the summary, collapsible in OBSIDIAN and MKDOCS) and DETAILS_HEADING (the summary
+	// becomes a heading one level below the preceding heading). An empty value is
+	// treated as DETAILS_HTML.
+	Details string `yaml:"Details"`
+
 	// StripLinkTitles determines whether to strip all title attributes from links,
 	// regardless of whether they were provided in the HTML.
 	// This ensures consistent output with the Python markdownify package.
-	StripLinkTitles bool
+	StripLinkTitles bool `yaml:"StripLinkTitles"`
 
 	// EscapeAsterisks determines whether to escape * characters in the text.
 	// This prevents them from being interpreted as Markdown formatting.
-	EscapeAsterisks bool
+	EscapeAsterisks bool `yaml:"EscapeAsterisks"`
 
 	// EscapeUnderscores determines whether to escape _ characters in the text.
 	// This prevents them from being interpreted as Markdown formatting.
-	EscapeUnderscores bool
+	EscapeUnderscores bool `yaml:"EscapeUnderscores"`
 
 	// EscapeMisc determines whether to escape other special characters in the text.
 	// This includes characters like #, >, -, +, etc. that have special meaning in Markdown.
-	EscapeMisc bool
+	EscapeMisc bool `yaml:"EscapeMisc"`
+
+	// Exclude is a list of CSS selectors, such as "nav", ".ads", "#comments" or
+	// "[aria-hidden=true]". Matching elements are removed with all their content
+	// before the document is converted, including when they are inside Include.
+	Exclude []string `yaml:"Exclude,omitempty"`
+
+	// Flavor specifies the Markdown dialect to target.
+	// Valid values are COMMONMARK, GFM, PANDOC, MARKDOWN_EXTRA, KRAMDOWN, MKDOCS and OBSIDIAN.
+	// Syntax extensions such as definition lists are only used when the flavor supports them,
+	// otherwise a CommonMark-compatible fallback is emitted. An empty value is treated as GFM.
+	Flavor string `yaml:"Flavor"`
+
+	// Footnotes determines whether to convert footnote markup to Markdown footnotes.
+	// References such as <sup><a href="#fn1">1</a></sup> that point into a footnote
+	// section (Pandoc, Hugo, Wikipedia and WordPress markup) become [^1], and the
+	// definitions are collected as "[^1]: text" at the end of the document with their
+	// backlinks removed. Footnotes are not converted when Flavor is COMMONMARK.
+	Footnotes bool `yaml:"Footnotes"`
+
+	// FrontMatter specifies the format of a front matter block built from the document
+	// metadata (title, description, author, publication dates, keywords, og:image,
+	// canonical link and language) in the <head>.
+	// Valid values are YAML, TOML, JSON, or "" (no front matter).
+	FrontMatter string `yaml:"FrontMatter"`
+
+	// FrontMatterCallback is a function that can add, change or remove front matter fields
+	// before they are serialized. It receives the fields extracted from the document and
+	// the parsed document, and returns the fields to write. Values may be strings, numbers,
+	// booleans, string slices or nested maps.
+	FrontMatterCallback func(fields map[string]any, doc *html.Node) map[string]any `json:"-" yaml:"-"`
+
+	// HeadingIDs determines whether to keep the ids of headings (from the id attribute
+	// or an <a id> or <a name> anchor inside the heading) in the output. Flavors with
+	// heading attributes (PANDOC, MARKDOWN_EXTRA, KRAMDOWN, MKDOCS) get "## Install {#install}",
+	// other flavors get an inline anchor: "## <a id="install"></a>Install".
+	HeadingIDs bool `yaml:"HeadingIDs"`
 
 	// HeadingStyle specifies the style to use for headings.
 	// Valid values are ATX (# Heading), ATX_CLOSED (# Heading #), and UNDERLINED (Heading\n=====).
-	HeadingStyle string
+	HeadingStyle string `yaml:"HeadingStyle"`
+
+	// HeadingOffset is added to the level of every heading, so converted pages can be
+	// nested in an existing document hierarchy. For example, an offset of 1 turns <h1>
+	// into ## headings. Levels are limited to 1-6. The offset is applied after
+	// NormalizeHeadingLevels and DemoteExtraH1.
+	HeadingOffset int `yaml:"HeadingOffset"`
+
+	// NormalizeHeadingLevels determines whether to renumber headings by their nesting:
+	// the first heading becomes level 1, and each heading is one level below the closest
+	// preceding heading with a lower level, so skipped levels are closed. A page using
+	// <h2> and <h4> gets # and ## headings.
+	NormalizeHeadingLevels bool `yaml:"NormalizeHeadingLevels"`
+
+	// DemoteExtraH1 determines whether to turn every level 1 heading after the first
+	// into a level 2 heading, so the document has a single title.
+	DemoteExtraH1 bool `yaml:"DemoteExtraH1"`
+
+	// Include is a list of CSS selectors, such as "article .content". When set,
+	// only the matching elements of the body are converted, in document order,
+	// and everything else in the body is removed. If empty, the whole body is converted.
+	Include []string `yaml:"Include,omitempty"`
 
 	// KeepInlineImagesIn is a list of tags in which to keep inline images.
 	// By default, images are converted to Markdown image syntax, but this option
 	// allows for keeping the original HTML for images within specified tags.
-	KeepInlineImagesIn []string
+	KeepInlineImagesIn []string `yaml:"KeepInlineImagesIn"`
+
+	// Math determines whether to convert equations to TeX math.
+	// MathJax <script type="math/tex"> blocks, KaTeX output and <math> MathML elements
+	// are converted to TeX wrapped in InlineMathDelimiters or DisplayMathDelimiters.
+	// The TeX annotation is used when the markup contains one; otherwise simple MathML
+	// is translated to TeX. Rendered MathJax and KaTeX output is dropped.
+	Math bool `yaml:"Math"`
+
+	// InlineMathDelimiters specifies the opening and closing delimiters for inline math.
+	// For example, {"$", "$"} or {`\(`, `\)`}.
+	InlineMathDelimiters [2]string `yaml:"InlineMathDelimiters"`
+
+	// DisplayMathDelimiters specifies the opening and closing delimiters for display math.
+	// For example, {"$$", "$$"} or {`\[`, `\]`}.
+	DisplayMathDelimiters [2]string `yaml:"DisplayMathDelimiters"`
+
+	// MaxInputSize is the largest HTML input accepted, in bytes.
+	// Larger inputs fail with an error wrapping ErrInputTooLarge. 0 means no limit.
+	MaxInputSize int `yaml:"MaxInputSize"`
+
+	// MaxDepth is the deepest element nesting accepted, counting the <html> and
+	// <body> elements added by the parser. Deeper documents fail with an error
+	// wrapping ErrNestingTooDeep. The nesting is estimated from the markup
+	// before parsing, since parsing deeply nested markup is slow, and checked
+	// again on the parsed document. 0 means no limit.
+	MaxDepth int `yaml:"MaxDepth"`
+
+	// MaxOutputSize is the largest Markdown output produced, in bytes. The
+	// conversion stops with an error wrapping ErrOutputTooLarge as soon as the
+	// Markdown of any part of the document exceeds it. 0 means no limit.
+	MaxOutputSize int `yaml:"MaxOutputSize"`
 
 	// NewlineStyle specifies the style to use for line breaks.
 	// Valid values are SPACES (two spaces at end of line) and BACKSLASH (backslash at end of line).
-	NewlineStyle string
+	NewlineStyle string `yaml:"NewlineStyle"`
 
 	// NormalizeNewlines determines whether to normalize multiple consecutive newlines
 	// to a maximum of 2. This helps maintain consistent spacing in the output.
-	NormalizeNewlines bool
+	NormalizeNewlines bool `yaml:"NormalizeNewlines"`
+
+	// SemanticLineBreaks determines whether to reflow paragraph and list item text
+	// so that each sentence starts on a new line ("semantic line breaks"). This keeps
+	// diffs of the generated Markdown sentence-granular. Abbreviations, decimals and
+	// inline code are not treated as sentence boundaries. When enabled, Wrap is
+	// ignored for paragraphs.
+	SemanticLineBreaks bool `yaml:"SemanticLineBreaks"`
+
+	// SemanticLineBreakClauses determines whether SemanticLineBreaks also starts a
+	// new line after clause punctuation (semicolons and colons).
+	SemanticLineBreakClauses bool `yaml:"SemanticLineBreakClauses"`
+
+	// Readability determines whether to convert only the main content of the page,
+	// leaving out headers, footers, navigation, sidebars, cookie banners and ads.
+	// The main content is found by scoring elements on the length of their text
+	// and their link density, preferring <article> and <main>; see FindMainContent.
+	// It is applied after Include and Exclude.
+	Readability bool `yaml:"Readability"`
+
+	// RewriteAnchorLinks determines whether to rewrite same-document links (href="#...")
+	// that point at a heading, so they match the anchor the heading has in the output:
+	// its explicit id when HeadingIDs is set, otherwise the slug the chosen Flavor
+	// generates for it. MARKDOWN_EXTRA generates no slugs, so its links are only
+	// rewritten with HeadingIDs.
+	RewriteAnchorLinks bool `yaml:"RewriteAnchorLinks"`
 
 	// Strip is a list of tags to strip from the output. If nil, no tags are stripped.
-	// Stripped tags are removed completely, including their content.
-	Strip []string
+	// Stripped tags are not converted to Markdown, but their content is kept.
+	Strip []string `yaml:"Strip"`
 
 	// StripDocument specifies how to handle whitespace at the document level.
 	// Valid values are LSTRIP (remove leading newlines), RSTRIP (remove trailing newlines),
 	// STRIP (remove both), or "" (don't strip).
-	StripDocument string
+	StripDocument string `yaml:"StripDocument"`
 
 	// StrongEmSymbol specifies the symbol to use for strong and emphasis formatting.
 	// Valid values are ASTERISK (*emphasis* and **strong**) and UNDERSCORE (_emphasis_ and __strong__).
-	StrongEmSymbol string
+	// An empty value is treated as ASTERISK.
+	StrongEmSymbol string `yaml:"StrongEmSymbol"`
 
 	// SubSymbol specifies the symbol to use for subscript text.
 	// If empty, subscript is not converted to Markdown.
-	SubSymbol string
+	SubSymbol string `yaml:"SubSymbol"`
 
 	// SupSymbol specifies the symbol to use for superscript text.
 	// If empty, superscript is not converted to Markdown.
-	SupSymbol string
+	SupSymbol string `yaml:"SupSymbol"`
 
 	// TableInferHeader determines whether to infer table headers when not explicitly defined.
 	// When true, the first row of a table is treated as a header row if no <th> tags are present.
-	TableInferHeader bool
-	
+	TableInferHeader bool `yaml:"TableInferHeader"`
+
 	// DeduplicateHeadings determines whether to remove duplicate headings.
-	// When true, subsequent identical headings will be removed from the output.
+	// When true, a heading with the same level and text as an earlier heading in the
+	// scope set by DeduplicateHeadingsScope is removed from the output.
 	// This helps match the behavior of the Python markdownify package.
-	DeduplicateHeadings bool
+	DeduplicateHeadings bool `yaml:"DeduplicateHeadings"`
+
+	// DeduplicateHeadingsScope specifies which earlier headings a heading is compared with.
+	// Valid values are DEDUPLICATE_DOCUMENT (all earlier headings), DEDUPLICATE_SECTION
+	// (earlier headings in the same parent section, so "Examples" may repeat under
+	// different sections) and DEDUPLICATE_CONSECUTIVE (only the heading right before it).
+	// An empty value is treated as DEDUPLICATE_DOCUMENT.
+	DeduplicateHeadingsScope string `yaml:"DeduplicateHeadingsScope"`
+
+	// ReportDuplicateHeadings determines whether duplicate headings are kept and only
+	// reported in the warnings of ConvertResult, instead of being removed.
+	ReportDuplicateHeadings bool `yaml:"ReportDuplicateHeadings"`
+
+	// TOC specifies where to insert a table of contents generated from the converted
+	// headings. Each entry links to the heading's anchor (see Heading.Slug).
+	// Valid values are TOC_PLACEHOLDER (replace a [TOC] paragraph), TOC_TOP,
+	// TOC_AFTER_H1, or "" (no table of contents).
+	TOC string `yaml:"TOC"`
+
+	// TOCMinLevel specifies the lowest heading level included in the table of contents.
+	TOCMinLevel int `yaml:"TOCMinLevel"`
+
+	// TOCMaxLevel specifies the highest heading level included in the table of contents.
+	TOCMaxLevel int `yaml:"TOCMaxLevel"`
 
 	// Wrap determines whether to wrap text at a specified width.
 	// When true, long lines are wrapped to improve readability.
-	Wrap bool
+	Wrap bool `yaml:"Wrap"`
 
 	// WrapWidth specifies the width at which to wrap text when Wrap is true.
 	// This is measured in characters.
-	WrapWidth int
+	WrapWidth int `yaml:"WrapWidth"`
 }
 
 // DefaultOptions returns the default options for the markdown converter.
 // These defaults are designed to match the behavior of the Python markdownify package.
 func DefaultOptions() Options {
 	return Options{
-		Autolinks:           true,
-		Bullets:             "*+-",
-		CodeLanguage:        "",
-		Convert:             nil,
-		DefaultTitle:        false,
-		StripLinkTitles:     true,  // Strip titles to match Python markdownify behavior
-		EscapeAsterisks:     true,
-		EscapeUnderscores:   true,
-		EscapeMisc:          false,
-		HeadingStyle:        UNDERLINED,
-		KeepInlineImagesIn:  []string{},
-		NewlineStyle:        SPACES,
-		NormalizeNewlines:   true,
-		Strip:               nil,
-		StripDocument:       LSTRIP,
-		StrongEmSymbol:      ASTERISK,
-		SubSymbol:           "",
-		SupSymbol:           "",
-		TableInferHeader:    true, // Match Python markdownify behavior
-		DeduplicateHeadings: true, // Match Python markdownify behavior
-		Wrap:                false,
-		WrapWidth:           80,
+		Autolinks:                true,
+		Bullets:                  "*+-",
+		Callouts:                 false,
+		CalloutRules:             nil,
+		CodeLanguage:             "",
+		Convert:                  nil,
+		DefaultTitle:             false,
+		Details:                  DETAILS_HTML,
+		StripLinkTitles:          true, // Strip titles to match Python markdownify behavior
+		EscapeAsterisks:          true,
+		EscapeUnderscores:        true,
+		EscapeMisc:               false,
+		Flavor:                   GFM,
+		Footnotes:                false,
+		FrontMatter:              "",
+		HeadingIDs:               false,
+		HeadingStyle:             UNDERLINED,
+		HeadingOffset:            0,
+		NormalizeHeadingLevels:   false,
+		DemoteExtraH1:            false,
+		KeepInlineImagesIn:       []string{},
+		Math:                     false,
+		InlineMathDelimiters:     [2]string{"$", "$"},
+		DisplayMathDelimiters:    [2]string{"$$", "$$"},
+		MaxInputSize:             0,
+		MaxDepth:                 0,
+		MaxOutputSize:            0,
+		NewlineStyle:             SPACES,
+		NormalizeNewlines:        true,
+		RewriteAnchorLinks:       false,
+		SemanticLineBreaks:       false,
+		Strip:                    nil,
+		StripDocument:            LSTRIP,
+		StrongEmSymbol:           ASTERISK,
+		SubSymbol:                "",
+		SupSymbol:                "",
+		TableInferHeader:         true, // Match Python markdownify behavior
+		DeduplicateHeadings:      true, // Match Python markdownify behavior
+		DeduplicateHeadingsScope: DEDUPLICATE_DOCUMENT,
+		ReportDuplicateHeadings:  false,
+		TOC:                      "",
+		TOCMinLevel:              1,
+		TOCMaxLevel:              6,
+		Wrap:                     false,
+		WrapWidth:                80,
+	}
+}
+
+// OptionError reports an invalid value of one of the Options fields.
+type OptionError struct {
+	// Option is the name of the invalid field, such as "HeadingStyle"
+	Option string
+	// Value is the invalid value
+	Value any
+	// Reason describes the valid values
+	Reason string
+}
+
+// Error returns the error message.
+func (e *OptionError) Error() string {
+	return fmt.Sprintf("markdownify: invalid %s %#v: %s", e.Option, e.Value, e.Reason)
+}
+
+// Validate checks the options for unknown enum values and values the
+// converter cannot use. It returns nil for valid options, or an error
+// joining an *OptionError for each invalid field.
+//
+// The empty string is accepted for every enum option, as the zero value of
+// an Options literal. NewConverter calls Validate, so it is only needed to
+// check options ahead of time, such as options loaded from a file.
+func (o Options) Validate() error {
+	var errs []error
+	invalid := func(option string, value any, reason string) {
+		errs = append(errs, &OptionError{Option: option, Value: value, Reason: reason})
+	}
+	oneOf := func(option string, value string, valid ...string) {
+		if value != "" && !slices.Contains(valid, value) {
+			invalid(option, value, "must be one of "+quoteList(valid))
+		}
+	}
+
+	oneOf("HeadingStyle", o.HeadingStyle, ATX, ATX_CLOSED, UNDERLINED)
+	oneOf("NewlineStyle", o.NewlineStyle, SPACES, BACKSLASH)
+	oneOf("StrongEmSymbol", o.StrongEmSymbol, ASTERISK, UNDERSCORE)
+	oneOf("StripDocument", o.StripDocument, LSTRIP, RSTRIP, STRIP)
+	oneOf("Flavor", o.Flavor, COMMONMARK, GFM, PANDOC, MARKDOWN_EXTRA, KRAMDOWN, MKDOCS, OBSIDIAN)
+	oneOf("FrontMatter", o.FrontMatter, YAML, TOML, JSON)
+	oneOf("TOC", o.TOC, TOC_PLACEHOLDER, TOC_TOP, TOC_AFTER_H1)
+	oneOf("DeduplicateHeadingsScope", o.DeduplicateHeadingsScope, DEDUPLICATE_DOCUMENT, DEDUPLICATE_SECTION, DEDUPLICATE_CONSECUTIVE)
+	oneOf("Details", o.Details, DETAILS_HTML, DETAILS_CALLOUT, DETAILS_HEADING)
+
+	if o.Bullets == "" {
+		invalid("Bullets", o.Bullets, "must contain at least one bullet character")
+	}
+	if o.WrapWidth < 0 {
+		invalid("WrapWidth", o.WrapWidth, "must not be negative")
+	}
+	if o.TOC != "" {
+		if o.TOCMinLevel < 1 || o.TOCMinLevel > 6 {
+			invalid("TOCMinLevel", o.TOCMinLevel, "must be between 1 and 6")
+		}
+		if o.TOCMaxLevel < 1 || o.TOCMaxLevel > 6 {
+			invalid("TOCMaxLevel", o.TOCMaxLevel, "must be between 1 and 6")
+		} else if o.TOCMaxLevel < o.TOCMinLevel {
+			invalid("TOCMaxLevel", o.TOCMaxLevel, "must not be lower than TOCMinLevel")
+		}
+	}
+	if o.MaxInputSize < 0 {
+		invalid("MaxInputSize", o.MaxInputSize, "must not be negative")
+	}
+	if o.MaxDepth < 0 {
+		invalid("MaxDepth", o.MaxDepth, "must not be negative")
+	}
+	if o.MaxOutputSize < 0 {
+		invalid("MaxOutputSize", o.MaxOutputSize, "must not be negative")
+	}
+	if len(o.Strip) > 0 && o.Convert != nil {
+		invalid("Strip", o.Strip, "cannot be combined with Convert, specify either tags to strip or tags to convert")
+	}
+	for i, selector := range o.Include {
+		if _, err := cascadia.ParseGroup(selector); err != nil {
+			invalid(fmt.Sprintf("Include[%d]", i), selector, "is not a valid CSS selector: "+err.Error())
+		}
+	}
+	for i, selector := range o.Exclude {
+		if _, err := cascadia.ParseGroup(selector); err != nil {
+			invalid(fmt.Sprintf("Exclude[%d]", i), selector, "is not a valid CSS selector: "+err.Error())
+		}
+	}
+	for i, rule := range o.CalloutRules {
+		if rule.Tag == "" && rule.Class == "" {
+			invalid(fmt.Sprintf("CalloutRules[%d]", i), rule, "must have a Tag or a Class")
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// quoteList formats valid values for an error message.
+func quoteList(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = fmt.Sprintf("%q", value)
 	}
+	return strings.Join(quoted, ", ")
 }
@@ -0,0 +1,154 @@
+package gomarkdownify
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestOptionsValidate tests reporting invalid options
+func TestOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		options func(*Options)
+		invalid []string
+	}{
+		{
+			name:    "defaults",
+			options: func(o *Options) {},
+		},
+		{
+			name: "empty enums",
+			options: func(o *Options) {
+				o.HeadingStyle = ""
+				o.StripDocument = ""
+				o.Flavor = ""
+				o.Details = ""
+				o.StrongEmSymbol = ""
+			},
+		},
+		{
+			name:    "unknown heading style",
+			options: func(o *Options) { o.HeadingStyle = "atxx" },
+			invalid: []string{"HeadingStyle"},
+		},
+		{
+			name: "unknown enums",
+			options: func(o *Options) {
+				o.NewlineStyle = "tab"
+				o.StrongEmSymbol = "**"
+				o.StripDocument = "both"
+				o.Flavor = "github"
+				o.FrontMatter = "yml"
+				o.TOC = "bottom"
+				o.DeduplicateHeadingsScope = "page"
+				o.Details = "summary"
+			},
+			invalid: []string{"NewlineStyle", "StrongEmSymbol", "StripDocument", "Flavor", "FrontMatter", "TOC", "DeduplicateHeadingsScope", "Details"},
+		},
+		{
+			name:    "empty bullets",
+			options: func(o *Options) { o.Bullets = "" },
+			invalid: []string{"Bullets"},
+		},
+		{
+			name: "negative sizes",
+			options: func(o *Options) {
+				o.WrapWidth = -1
+				o.MaxInputSize = -1
+				o.MaxDepth = -1
+				o.MaxOutputSize = -1
+			},
+			invalid: []string{"WrapWidth", "MaxInputSize", "MaxDepth", "MaxOutputSize"},
+		},
+		{
+			name: "table of contents levels",
+			options: func(o *Options) {
+				o.TOC = TOC_TOP
+				o.TOCMinLevel = 3
+				o.TOCMaxLevel = 2
+			},
+			invalid: []string{"TOCMaxLevel"},
+		},
+		{
+			name: "levels ignored without table of contents",
+			options: func(o *Options) {
+				o.TOCMinLevel = 0
+				o.TOCMaxLevel = 9
+			},
+		},
+		{
+			name: "strip and convert",
+			options: func(o *Options) {
+				o.Strip = []string{"a"}
+				o.Convert = []string{"b"}
+			},
+			invalid: []string{"Strip"},
+		},
+		{
+			name:    "callout rule without tag or class",
+			options: func(o *Options) { o.CalloutRules = []CalloutRule{{Tag: "aside"}, {TypePrefix: "alert-"}} },
+			invalid: []string{"CalloutRules[1]"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := DefaultOptions()
+			tt.options(&options)
+			err := options.Validate()
+			if len(tt.invalid) == 0 {
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("Expected errors for %v", tt.invalid)
+			}
+
+			var reported []string
+			for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+				var optionErr *OptionError
+				if !errors.As(e, &optionErr) {
+					t.Fatalf("Expected an *OptionError, got %T", e)
+				}
+				reported = append(reported, optionErr.Option)
+			}
+			if strings.Join(reported, ",") != strings.Join(tt.invalid, ",") {
+				t.Errorf("Expected errors for %v, got %v", tt.invalid, reported)
+			}
+		})
+	}
+}
+
+// TestNewConverterInvalidOptions tests that invalid options are reported by the constructors
+func TestNewConverterInvalidOptions(t *testing.T) {
+	options := DefaultOptions()
+	options.HeadingStyle = "atxx"
+
+	converter, err := NewConverter(options)
+	if converter != nil || err == nil {
+		t.Fatalf("Expected an error, got %v, %v", converter, err)
+	}
+	expected := `markdownify: invalid HeadingStyle "atxx": must be one of "atx", "atx_closed", "underlined"`
+	if err.Error() != expected {
+		t.Errorf("Expected %q, got %q", expected, err.Error())
+	}
+
+	if _, err := Convert("<h1>Title</h1>", options); err == nil {
+		t.Error("Expected Convert to report invalid options")
+	}
+}
+
+// TestEmptyStrongEmSymbol tests that an empty StrongEmSymbol keeps strong and emphasis markup
+func TestEmptyStrongEmSymbol(t *testing.T) {
+	result, err := Convert("<ul><li><strong>a</strong> <em>b</em></li></ul>", Options{Bullets: "*", StripDocument: STRIP})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "* **a** *b*"
+	if result != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+}
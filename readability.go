@@ -0,0 +1,357 @@
+package gomarkdownify
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/html"
+)
+
+// Thresholds of the main content extraction.
+const (
+	// minParagraphChars is the shortest paragraph, in non-space characters,
+	// that counts towards the score of its ancestors
+	minParagraphChars = 25
+	// maxBoilerplateLinkDensity is the link density above which a list
+	// inside the main content can be navigation
+	maxBoilerplateLinkDensity = 0.5
+	// maxBoilerplateLinkChars is the longest average link text, in non-space
+	// characters, of a list removed as navigation, such as tags or categories
+	maxBoilerplateLinkChars = 20
+)
+
+// textStats describes the text inside an element.
+type textStats struct {
+	// chars is the number of non-space characters
+	chars int
+	// linkChars is the number of non-space characters inside links
+	linkChars int
+	// links is the number of links with text
+	links int
+	// commas is the number of commas, a sign of running prose
+	commas int
+}
+
+// linkDensity returns the share of the text that is inside links.
+func (s textStats) linkDensity() float64 {
+	if s.chars == 0 {
+		return 0
+	}
+	return float64(s.linkChars) / float64(s.chars)
+}
+
+// FindMainContent returns the element holding the main content of a parsed
+// document, the node Options.Readability converts. It is exported so the
+// choice can be inspected when debugging a page.
+//
+// Paragraphs add a score to their parent and grandparent based on their
+// length and number of commas, containers start from a score based on their
+// tag and on class names such as "content" or "sidebar", and the scores are
+// reduced by the link density of the container. When the best container is
+// inside an <article>, a <main> or an element with role="main", the closest
+// such element is chosen instead, so headlines and bylines next to the text
+// are kept.
+//
+// Parameters:
+//   - doc: The root of the parsed document.
+//
+// Returns:
+//   - The main content element, or the body when no candidate is found, or
+//     nil when the document has no body.
+func FindMainContent(doc *html.Node) *html.Node {
+	body := findBody(doc)
+	if body == nil {
+		return nil
+	}
+	stats := make(map[*html.Node]textStats)
+	collectTextStats(body, stats)
+	return findMainContent(body, stats)
+}
+
+// findMainContent chooses the main content element below body, see
+// FindMainContent.
+func findMainContent(body *html.Node, stats map[*html.Node]textStats) *html.Node {
+	scores := make(map[*html.Node]float64)
+	var candidates []*html.Node
+	addScore := func(n *html.Node, score float64) {
+		if n == nil || n.Type != html.ElementNode || n.Data == "html" {
+			return
+		}
+		if _, ok := scores[n]; !ok {
+			scores[n] = initialScore(n)
+			candidates = append(candidates, n)
+		}
+		scores[n] += score
+	}
+
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type != html.ElementNode || isBoilerplate(child) {
+				continue
+			}
+			if isParagraph(child) {
+				s := stats[child]
+				if s.chars >= minParagraphChars {
+					score := 1 + float64(s.commas) + float64(min(s.chars/100, 3))
+					addScore(child.Parent, score)
+					addScore(child.Parent.Parent, score/2)
+				}
+			}
+			walk(child)
+		}
+	}
+	walk(body)
+
+	var best *html.Node
+	bestScore := 0.0
+	for _, n := range candidates {
+		score := scores[n] * (1 - stats[n].linkDensity())
+		if best == nil || score > bestScore {
+			best, bestScore = n, score
+		}
+	}
+
+	if best == nil {
+		// Without paragraphs to score, rely on the markup alone
+		if semantic := findSemantic(body, stats); semantic != nil {
+			return semantic
+		}
+		return body
+	}
+
+	// Prefer the closest semantic container of the best candidate
+	for n := best; n != nil && n != body; n = n.Parent {
+		if isSemanticMain(n) {
+			return n
+		}
+	}
+	// or a semantic container inside it that holds most of its text
+	if semantic := findSemantic(best, stats); semantic != nil && stats[semantic].chars*2 >= stats[best].chars {
+		return semantic
+	}
+	return best
+}
+
+// findSemantic returns the first <article>, or else the first <main> or
+// role="main" element below n that has text, or nil.
+func findSemantic(n *html.Node, stats map[*html.Node]textStats) *html.Node {
+	var article, main *html.Node
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		for child := n.FirstChild; child != nil && article == nil; child = child.NextSibling {
+			if child.Type != html.ElementNode {
+				continue
+			}
+			if isSemanticMain(child) && stats[child].chars > 0 {
+				if child.Data == "article" {
+					article = child
+					return
+				}
+				if main == nil {
+					main = child
+				}
+			}
+			walk(child)
+		}
+	}
+	walk(n)
+	if article != nil {
+		return article
+	}
+	return main
+}
+
+// isSemanticMain reports whether the markup marks n as main content.
+func isSemanticMain(n *html.Node) bool {
+	return n.Data == "article" || n.Data == "main" || strings.EqualFold(getAttr(n, "role"), "main")
+}
+
+// isParagraph reports whether n is a block of text whose length is scored:
+// a paragraph, preformatted text, a table cell, or a div without blocks.
+func isParagraph(n *html.Node) bool {
+	switch n.Data {
+	case "p", "pre", "td", "blockquote":
+		return true
+	case "div":
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type == html.ElementNode && blockElements[child.Data] {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// blockElements are the elements that stop a div from counting as a paragraph.
+var blockElements = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true, "div": true,
+	"dl": true, "figure": true, "footer": true, "form": true, "h1": true, "h2": true,
+	"h3": true, "h4": true, "h5": true, "h6": true, "header": true, "main": true,
+	"nav": true, "ol": true, "p": true, "pre": true, "section": true, "table": true,
+	"ul": true,
+}
+
+// initialScore returns the score of a container before its paragraphs are
+// added, based on its tag and class names.
+func initialScore(n *html.Node) float64 {
+	score := classWeight(n)
+	switch n.Data {
+	case "article", "main":
+		score += 10
+	case "div", "section":
+		score += 5
+	case "pre", "td", "blockquote":
+		score += 3
+	case "address", "ol", "ul", "dl", "dd", "dt", "li", "form":
+		score -= 3
+	case "h1", "h2", "h3", "h4", "h5", "h6", "th":
+		score -= 5
+	}
+	return score
+}
+
+// classWeight scores the class and id of n: names of content raise the
+// score, names of page furniture lower it.
+func classWeight(n *html.Node) float64 {
+	weight := 0.0
+	for _, name := range []string{getAttr(n, "class"), getAttr(n, "id")} {
+		if name == "" {
+			continue
+		}
+		if reUnlikelyCandidate.MatchString(name) {
+			weight -= 25
+		}
+		if rePositiveCandidate.MatchString(name) {
+			weight += 25
+		}
+	}
+	return weight
+}
+
+// isBoilerplate reports whether n is page furniture that is never part of
+// the main content: navigation, sidebars, footers, forms, embedded frames,
+// and elements whose class or id names such furniture, unless they hold the
+// page headline.
+func isBoilerplate(n *html.Node) bool {
+	switch n.Data {
+	case "nav", "aside", "form", "button", "iframe", "script", "style", "noscript", "template":
+		return true
+	case "footer":
+		// The footer of a quotation is its attribution
+		return n.Parent == nil || n.Parent.Data != "blockquote"
+	}
+	switch strings.ToLower(getAttr(n, "role")) {
+	case "navigation", "complementary", "dialog", "alertdialog", "search":
+		return true
+	}
+	class := getAttr(n, "class") + " " + getAttr(n, "id")
+	return reUnlikelyCandidate.MatchString(class) && !rePositiveCandidate.MatchString(class) && !hasDescendant(n, "h1")
+}
+
+// hasDescendant reports whether an element with the given tag is below n.
+func hasDescendant(n *html.Node, tag string) bool {
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.ElementNode && (child.Data == tag || hasDescendant(child, tag)) {
+			return true
+		}
+	}
+	return false
+}
+
+// collectTextStats records the text statistics of n and every element below
+// it in a single pass, and returns those of n. Scripts and styles have no text.
+func collectTextStats(n *html.Node, stats map[*html.Node]textStats) textStats {
+	var s textStats
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		switch child.Type {
+		case html.TextNode:
+			for _, r := range child.Data {
+				if !unicode.IsSpace(r) {
+					s.chars++
+				}
+			}
+			s.commas += strings.Count(child.Data, ",") + strings.Count(child.Data, "，")
+		case html.ElementNode:
+			switch child.Data {
+			case "script", "style", "noscript", "template":
+				continue
+			}
+			childStats := collectTextStats(child, stats)
+			s.chars += childStats.chars
+			s.commas += childStats.commas
+			if child.Data == "a" {
+				s.linkChars += childStats.chars
+				if childStats.chars > 0 {
+					s.links++
+				}
+			} else {
+				s.linkChars += childStats.linkChars
+				s.links += childStats.links
+			}
+		}
+	}
+	stats[n] = s
+	return s
+}
+
+// cleanMainContent removes the boilerplate left inside the main content:
+// page furniture (see isBoilerplate), and lists of short links, such as tag
+// clouds and category links.
+func cleanMainContent(n *html.Node, stats map[*html.Node]textStats) {
+	for child := n.FirstChild; child != nil; {
+		next := child.NextSibling
+		if child.Type == html.ElementNode {
+			if isBoilerplate(child) || isLinkList(child, stats[child]) {
+				n.RemoveChild(child)
+			} else {
+				cleanMainContent(child, stats)
+			}
+		}
+		child = next
+	}
+}
+
+// isLinkList reports whether a list or container is made mostly of short
+// links. Paragraphs, and lists of longer links such as references, are
+// content even when they only hold links.
+func isLinkList(n *html.Node, s textStats) bool {
+	switch n.Data {
+	case "div", "section", "ul", "ol":
+		return s.links > 0 && s.linkDensity() > maxBoilerplateLinkDensity &&
+			s.linkChars <= s.links*maxBoilerplateLinkChars
+	}
+	return false
+}
+
+// extractMainContent keeps only the main content of the body of a parsed
+// document, see Options.Readability. The head is kept for the metadata.
+//
+// Parameters:
+//   - doc: The root of the parsed document, modified in place.
+//
+// Returns:
+//   - The main content element, or nil when the document has no body.
+func (c *Converter) extractMainContent(doc *html.Node) *html.Node {
+	body := findBody(doc)
+	if body == nil {
+		return nil
+	}
+	stats := make(map[*html.Node]textStats)
+	collectTextStats(body, stats)
+	content := findMainContent(body, stats)
+	if content == body {
+		c.warn("no main content found, converting the whole body")
+	}
+
+	ancestors := make(map[*html.Node]bool)
+	for p := content.Parent; p != nil && p != body; p = p.Parent {
+		ancestors[p] = true
+	}
+	if content != body {
+		pruneExcept(body, map[*html.Node]bool{content: true}, ancestors)
+	}
+	cleanMainContent(content, stats)
+	return content
+}
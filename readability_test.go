@@ -0,0 +1,158 @@
+package gomarkdownify
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+)
+
+// TestFindMainContent tests choosing the main content of the sample pages
+func TestFindMainContent(t *testing.T) {
+	tests := []struct {
+		page  string
+		tag   string
+		class string
+	}{
+		{page: "blog.html", tag: "article", class: "post"},
+		{page: "docs.html", tag: "div", class: "content"},
+		{page: "wiki.html", tag: "div", class: "mw-body"},
+		{page: "news.html", tag: "article"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.page, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("testdata", "pages", tt.page))
+			if err != nil {
+				t.Fatal(err)
+			}
+			doc, err := html.Parse(strings.NewReader(string(data)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			n := FindMainContent(doc)
+			if n == nil || n.Data != tt.tag || getAttr(n, "class") != tt.class {
+				t.Errorf("Expected <%s class=%q>, got %v", tt.tag, tt.class, n)
+			}
+		})
+	}
+}
+
+// TestReadability tests converting only the main content
+func TestReadability(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "pages", "news.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	converter := newTestConverter(t, Options{
+		Bullets:      "*+-",
+		HeadingStyle: ATX,
+		Readability:  true,
+	})
+	result, err := converter.ConvertResult(string(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{"# City Council Approves New Cycling Network", "| Phase | Routes |", "> We are not against cyclists."} {
+		if !strings.Contains(result.Markdown, expected) {
+			t.Errorf("Expected %q in the main content, got %q", expected, result.Markdown)
+		}
+	}
+	for _, unexpected := range []string{"cookies", "Politics", "Advertisement", "Related", "Share on X", "morning briefing", "All rights reserved", "Example Gazette"} {
+		if strings.Contains(result.Markdown, unexpected) {
+			t.Errorf("Unexpected %q in the main content, got %q", unexpected, result.Markdown)
+		}
+	}
+	if result.MainContent == nil || result.MainContent.Data != "article" {
+		t.Errorf("Expected the article as main content, got %v", result.MainContent)
+	}
+	if result.Title != "City Council Approves New Cycling Network | The Example Gazette" {
+		t.Errorf("Expected the title from the head, got %q", result.Title)
+	}
+}
+
+// TestReadabilityScoring tests finding the main content without semantic elements
+func TestReadabilityScoring(t *testing.T) {
+	tests := []struct {
+		name     string
+		html     string
+		options  []Option
+		expected string
+		warnings int
+	}{
+		{
+			name: "text density",
+			html: `<div id="top"><a href="/">Home</a> <a href="/about">About</a></div>
+<div class="wrapper">
+<div class="links"><p><a href="/a">A very long link to another story on this site</a></p><p><a href="/b">And another long link to yet another story</a></p></div>
+<div id="story"><p>The first paragraph of the story, long enough to be scored, with commas, clauses, and detail.</p>
+<p>The second paragraph of the story continues the text, with a <a href="/x">link</a> in it.</p></div>
+</div>
+<div class="footer">Copyright</div>`,
+			expected: "The first paragraph of the story, long enough to be scored, with commas, clauses, and detail.\n\n" +
+				"The second paragraph of the story continues the text, with a [link](/x) in it.",
+		},
+		{
+			name:     "semantic element without scored paragraphs",
+			html:     `<nav><a href="/">Home</a></nav><main><h1>Short</h1><p>Hi.</p></main><footer>Copyright</footer>`,
+			expected: "# Short\n\nHi.",
+		},
+		{
+			name:     "link lists removed from the content",
+			html:     `<article><h1>Title</h1><p>A paragraph that is long enough to be scored as content.</p><ul class="tags"><li><a href="/t/a">alpha</a></li><li><a href="/t/b">beta</a></li></ul><blockquote><p>Quote</p><footer>Author</footer></blockquote></article>`,
+			expected: "# Title\n\nA paragraph that is long enough to be scored as content.\n\n> Quote\n>\n> Author",
+		},
+		{
+			name: "link-only content kept",
+			html: `<article><h1>Report</h1><p>A paragraph that is long enough to be scored as content.</p>` +
+				`<p><a href="/report.pdf">Download the full annual report</a></p><h2>References</h2>` +
+				`<ul><li><a href="/a">Smith, 2020. Cycling in cities</a></li><li><a href="/b">Jones, 2021. Safe streets</a></li></ul></article>`,
+			expected: "# Report\n\nA paragraph that is long enough to be scored as content.\n\n[Download the full annual report](/report.pdf)\n\n" +
+				"## References\n\n* [Smith, 2020. Cycling in cities](/a)\n* [Jones, 2021. Safe streets](/b)",
+		},
+		{
+			name:     "applied after exclude",
+			html:     `<article><h1>Title</h1><p>A paragraph that is long enough to be scored as content.</p><p class="note">Editor's note</p></article>`,
+			options:  []Option{WithExclude(".note")},
+			expected: "# Title\n\nA paragraph that is long enough to be scored as content.",
+		},
+		{
+			name:     "no content",
+			html:     `<span>Hello</span>`,
+			expected: "Hello",
+			warnings: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := append([]Option{WithReadability(true), WithHeadingStyle(ATX), WithStripDocument(STRIP)}, tt.options...)
+			converter, err := NewConverter(options...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			result, err := converter.ConvertResult(tt.html)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Markdown != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, result.Markdown)
+			}
+			if len(result.Warnings) != tt.warnings {
+				t.Errorf("Expected %d warnings, got %v", tt.warnings, result.Warnings)
+			}
+		})
+	}
+
+	// The main content is only reported in readability mode
+	result, err := newTestConverter(t, DefaultOptions()).ConvertResult("<article><p>Text</p></article>")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.MainContent != nil {
+		t.Errorf("Expected no main content, got %v", result.MainContent)
+	}
+}
@@ -54,4 +54,23 @@ var (
 	// reEscapeMiscListItems matches numbered list items that need to be escaped.
 	// Used when EscapeMisc option is enabled.
 	reEscapeMiscListItems = regexp.MustCompile(`((?:\s|^)[0-9]{1,9})([.)](?:\s|$))`)
+
+	// reMarkdownBlockStart matches lines that begin a Markdown block other than
+	// a paragraph (list items, blockquotes, headings, code fences, tables and rules).
+	// Used to leave nested blocks untouched when reflowing sentences.
+	reMarkdownBlockStart = regexp.MustCompile(`^(?:[*+-][ \t]|[0-9]{1,9}[.)](?:[ \t]|$)|>|#{1,6}(?:[ \t]|$)|` + "```" + `|~~~|\||[=-]+[ \t]*$)`)
+
+	// reMultipleNewlines matches three or more consecutive newlines.
+	// Used for collapsing runs of blank lines into a single blank line.
+	reMultipleNewlines = regexp.MustCompile(`\n{3,}`)
+
+	// reUnlikelyCandidate matches class names and ids of page furniture, such as
+	// "sidebar", "ad-slot" or "cookie-banner".
+	// Used to skip boilerplate when looking for the main content.
+	reUnlikelyCandidate = regexp.MustCompile(`(?i)(^|[\s_-])(ads?|advert\w*|banner|breadcrumbs?|combx|comments?|community|cookies?|disqus|footer|gdpr|header|masthead|menu|modal|nav\w*|newsletter|outbrain|pager|pagination|popup|promo\w*|related|remark|replies|rss|share|sharing|shoutbox|sidebar|skyscraper|social|sponsor\w*|subscribe|taboola|toolbar|tweet|twitter|widget)($|[\s_-])`)
+
+	// rePositiveCandidate matches class names and ids of content, such as
+	// "article-body", "entry" or "post".
+	// Used to favor content when looking for the main content.
+	rePositiveCandidate = regexp.MustCompile(`(?i)(^|[\s_-])(article\w*|body|content|entry|h-entry|hentry|main|page|post|story|text)($|[\s_-])`)
 )
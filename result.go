@@ -0,0 +1,174 @@
+package gomarkdownify
+
+import (
+	"context"
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/html"
+)
+
+// Result is the structured outcome of a conversion. Besides the Markdown it
+// describes the document, so callers do not need to parse the Markdown again.
+type Result struct {
+	// Markdown is the converted document, identical to the output of Convert
+	Markdown string
+	// Title is the document title from the <head>, or the text of the first
+	// level 1 heading when the document has no title
+	Title string
+	// Metadata is the document metadata found in the <head>
+	Metadata Metadata
+	// Headings is the outline of the document. Each heading holds the headings
+	// of lower levels that follow it as children.
+	Headings []*Heading
+	// Links lists the links written to the Markdown in the order they appear,
+	// with their final URLs. Links in dropped content, such as a duplicate
+	// heading or a summary written as plain text, are not listed.
+	Links []Link
+	// Images lists the images written to the Markdown in the order they
+	// appear, with their final URLs
+	Images []Image
+	// WordCount is the number of words in the converted document
+	WordCount int
+	// Warnings lists problems found during conversion, such as dropped content
+	Warnings []string
+	// MainContent is the element converted by Readability, for debugging which
+	// part of the page was chosen. It belongs to the parsed document, with the
+	// rest of the body removed. Nil when Readability is not set.
+	MainContent *html.Node
+}
+
+// Heading is a heading in the document outline.
+type Heading struct {
+	// Level is the heading level, from 1 to 6
+	Level int
+	// Text is the plain text of the heading
+	Text string
+	// Slug is the anchor of the heading in the output, unique within the document:
+	// the slug generated by the chosen Flavor (GitHub-style by default), or the
+	// original id when HeadingIDs is set
+	Slug string
+	// Children are the headings nested below this heading
+	Children []*Heading
+}
+
+// Link is a link in the converted document.
+type Link struct {
+	// Text is the link text as written in the Markdown
+	Text string
+	// URL is the link target as written in the Markdown
+	URL string
+	// Title is the title attribute of the link, if any
+	Title string
+}
+
+// Image is an image in the converted document.
+type Image struct {
+	// Alt is the alternative text of the image
+	Alt string
+	// URL is the image source as written in the Markdown
+	URL string
+	// Title is the title attribute of the image, if any
+	Title string
+}
+
+// ConvertResult converts HTML to Markdown and returns the Markdown together
+// with the document title, the heading outline, the links and images in the
+// output, the word count and any warnings produced during conversion.
+//
+// Example:
+//
+//	converter, err := gomarkdownify.NewConverter(gomarkdownify.DefaultOptions())
+//	if err != nil {
+//	    // handle error
+//	}
+//	result, err := converter.ConvertResult(html)
+//	if err != nil {
+//	    // handle error
+//	}
+//	for _, link := range result.Links {
+//	    fmt.Println(link.URL)
+//	}
+func (c *Converter) ConvertResult(htmlContent string) (*Result, error) {
+	return c.convertResult(context.Background(), htmlContent)
+}
+
+// convertResult performs ConvertResult, stopping when ctx is cancelled.
+func (c *Converter) convertResult(ctx context.Context, htmlContent string) (*Result, error) {
+	markdown, state, err := c.convert(ctx, htmlContent)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := extractMetadata(state.doc)
+	result := &Result{
+		Markdown:    markdown,
+		Title:       metadata.Title,
+		Metadata:    metadata,
+		Headings:    buildHeadingTree(state.headings),
+		Links:       state.links,
+		Images:      state.images,
+		WordCount:   countWords(markdown),
+		Warnings:    state.warnings,
+		MainContent: state.mainContent,
+	}
+
+	if result.Title == "" {
+		for _, heading := range state.headings {
+			if heading.Level == 1 {
+				result.Title = heading.Text
+				break
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// buildHeadingTree nests a flat list of headings into a tree, where each
+// heading becomes a child of the closest preceding heading of a lower level.
+//
+// Parameters:
+//   - headings: The headings in document order.
+//
+// Returns:
+//   - The top-level headings.
+func buildHeadingTree(headings []*Heading) []*Heading {
+	var roots []*Heading
+	var stack []*Heading
+
+	for _, heading := range headings {
+		for len(stack) > 0 && stack[len(stack)-1].Level >= heading.Level {
+			stack = stack[:len(stack)-1]
+		}
+		if len(stack) == 0 {
+			roots = append(roots, heading)
+		} else {
+			parent := stack[len(stack)-1]
+			parent.Children = append(parent.Children, heading)
+		}
+		stack = append(stack, heading)
+	}
+
+	return roots
+}
+
+// countWords counts the words in Markdown text. Only whitespace separated
+// tokens that contain a letter or digit count, so list markers, rules and
+// table pipes are ignored.
+func countWords(text string) int {
+	count := 0
+	for _, field := range strings.Fields(text) {
+		if strings.IndexFunc(field, func(r rune) bool {
+			return unicode.IsLetter(r) || unicode.IsDigit(r)
+		}) >= 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// warn records a warning for the current conversion.
+func (c *Converter) warn(message string) {
+	c.state.warnings = append(c.state.warnings, message)
+}
@@ -0,0 +1,141 @@
+package gomarkdownify
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestConvertResult tests the structured conversion result
+func TestConvertResult(t *testing.T) {
+	html := `<html><head><title>Guide</title></head><body>
+<h1>Install</h1>
+<p>Get it from <a href="https://example.com/dl" title="Downloads">the site</a>.</p>
+<h2>On <em>Linux</em></h2>
+<img src="linux.png" alt="Tux">
+<h2>On Windows</h2>
+<h3>Notes</h3>
+<h1>Usage</h1>
+<h2>Notes</h2>
+<img alt="Missing">
+<h2>Notes</h2>
+</body></html>`
+
+	options := DefaultOptions()
+	options.HeadingStyle = ATX
+	converter := newTestConverter(t, options)
+
+	result, err := converter.ConvertResult(html)
+	if err != nil {
+		t.Fatalf("Failed to convert HTML: %v", err)
+	}
+
+	markdown, err := newTestConverter(t, options).Convert(html)
+	if err != nil {
+		t.Fatalf("Failed to convert HTML: %v", err)
+	}
+	if result.Markdown != markdown {
+		t.Errorf("Expected Markdown %q, got %q", markdown, result.Markdown)
+	}
+
+	if result.Title != "Guide" {
+		t.Errorf("Expected title %q, got %q", "Guide", result.Title)
+	}
+
+	expectedHeadings := []*Heading{
+		{Level: 1, Text: "Install", Slug: "install", Children: []*Heading{
+			{Level: 2, Text: "On Linux", Slug: "on-linux"},
+			{Level: 2, Text: "On Windows", Slug: "on-windows", Children: []*Heading{
+				{Level: 3, Text: "Notes", Slug: "notes"},
+			}},
+		}},
+		{Level: 1, Text: "Usage", Slug: "usage", Children: []*Heading{
+			{Level: 2, Text: "Notes", Slug: "notes-1"},
+		}},
+	}
+	if !reflect.DeepEqual(result.Headings, expectedHeadings) {
+		t.Errorf("Unexpected headings: %+v", result.Headings)
+	}
+
+	expectedLinks := []Link{{Text: "the site", URL: "https://example.com/dl", Title: "Downloads"}}
+	if !reflect.DeepEqual(result.Links, expectedLinks) {
+		t.Errorf("Expected links %+v, got %+v", expectedLinks, result.Links)
+	}
+
+	expectedImages := []Image{{Alt: "Tux", URL: "linux.png"}, {Alt: "Missing"}}
+	if !reflect.DeepEqual(result.Images, expectedImages) {
+		t.Errorf("Expected images %+v, got %+v", expectedImages, result.Images)
+	}
+
+	expectedWarnings := []string{`image without src (alt "Missing")`, `dropped duplicate heading "Notes"`}
+	if !reflect.DeepEqual(result.Warnings, expectedWarnings) {
+		t.Errorf("Expected warnings %q, got %q", expectedWarnings, result.Warnings)
+	}
+
+	if result.WordCount != countWords(result.Markdown) || result.WordCount == 0 {
+		t.Errorf("Unexpected word count %d", result.WordCount)
+	}
+
+	// Test the title fallback to the first level 1 heading
+	result, err = converter.ConvertResult("<h2>Intro</h2><h1>Main</h1>")
+	if err != nil {
+		t.Fatalf("Failed to convert HTML: %v", err)
+	}
+	if result.Title != "Main" {
+		t.Errorf("Expected title %q, got %q", "Main", result.Title)
+	}
+	if len(result.Links) != 0 || len(result.Warnings) != 0 {
+		t.Errorf("Expected state to be reset between conversions, got %+v", result)
+	}
+
+	// Test that only links written to the Markdown are listed
+	summary := `<details><summary><a href="s">Summary</a></summary><p><a href="b">Body</a></p></details>`
+	for _, test := range []struct {
+		details string
+		links   []Link
+	}{
+		{DETAILS_HTML, []Link{{Text: "Body", URL: "b"}}},
+		{DETAILS_CALLOUT, []Link{{Text: "Body", URL: "b"}}},
+		{DETAILS_HEADING, []Link{{Text: "Summary", URL: "s"}, {Text: "Body", URL: "b"}}},
+	} {
+		options.Details = test.details
+		result, err = newTestConverter(t, options).ConvertResult(summary)
+		if err != nil {
+			t.Fatalf("Failed to convert HTML: %v", err)
+		}
+		if !reflect.DeepEqual(result.Links, test.links) {
+			t.Errorf("Details %s: Expected links %+v, got %+v (Markdown %q)", test.details, test.links, result.Links, result.Markdown)
+		}
+	}
+}
+
+// TestSlugs tests GitHub-style slug generation
+func TestSlugs(t *testing.T) {
+	tests := map[string]string{
+		"Hello, World!":         "hello-world",
+		"  Already-slugged_id ": "already-slugged_id",
+		"Café & Crème":          "café--crème",
+		"C++ (1998)":            "c-1998",
+	}
+	for text, expected := range tests {
+		if result := slugify(text); result != expected {
+			t.Errorf("slugify(%q): Expected %q, got %q", text, expected, result)
+		}
+	}
+
+	s := newSlugger(GFM)
+	for _, expected := range []string{"intro", "intro-1", "intro-2"} {
+		if result := s.slug("Intro"); result != expected {
+			t.Errorf("Expected %q, got %q", expected, result)
+		}
+	}
+	if result := s.slug("Intro 1"); result != "intro-1-1" {
+		t.Errorf("Expected %q, got %q", "intro-1-1", result)
+	}
+}
+
+// TestCountWords tests the word count of Markdown text
+func TestCountWords(t *testing.T) {
+	if count := countWords("# Title\n\n* one two\n* [three](x)\n\n---\n\n| a | b |"); count != 6 {
+		t.Errorf("Expected 6 words, got %d", count)
+	}
+}
@@ -0,0 +1,101 @@
+package gomarkdownify
+
+import (
+	"github.com/andybalholm/cascadia"
+	"golang.org/x/net/html"
+)
+
+// compileSelectors parses a list of CSS selectors into one selector group
+// matching any of them.
+//
+// Parameters:
+//   - selectors: The selectors, each of which may itself be a comma separated group.
+//
+// Returns:
+//   - The selector group, or nil when there are no selectors.
+//   - The first parse error.
+func compileSelectors(selectors []string) (cascadia.SelectorGroup, error) {
+	var group cascadia.SelectorGroup
+	for _, selector := range selectors {
+		parsed, err := cascadia.ParseGroup(selector)
+		if err != nil {
+			return nil, err
+		}
+		group = append(group, parsed...)
+	}
+	return group, nil
+}
+
+// filterDocument applies the Include and Exclude selectors to a parsed
+// document before it is analyzed and converted.
+//
+// Both selectors are matched against the original tree, so removing one part
+// of the document does not change what the other selector matches. When
+// Include is set, only the matching elements of the body are kept, together
+// with the ancestors that connect them to the body; the head is kept for the
+// title and front matter. The elements matching Exclude are then removed with
+// their whole subtree, so exclusion wins over inclusion.
+//
+// Parameters:
+//   - doc: The root of the parsed document, modified in place.
+func (c *Converter) filterDocument(doc *html.Node) {
+	if c.include == nil && c.exclude == nil {
+		return
+	}
+
+	var excluded []*html.Node
+	if c.exclude != nil {
+		excluded = cascadia.QueryAll(doc, c.exclude)
+	}
+
+	if c.include != nil {
+		if body := findBody(doc); body != nil {
+			included := make(map[*html.Node]bool)
+			ancestors := make(map[*html.Node]bool)
+			for _, n := range cascadia.QueryAll(body, c.include) {
+				included[n] = true
+				for p := n.Parent; p != nil && p != body && !ancestors[p]; p = p.Parent {
+					ancestors[p] = true
+				}
+			}
+			pruneExcept(body, included, ancestors)
+		}
+	}
+
+	for _, n := range excluded {
+		if n.Parent != nil {
+			n.Parent.RemoveChild(n)
+		}
+	}
+}
+
+// pruneExcept removes the children of n that are neither included nor the
+// ancestor of an included node. Included nodes are kept with their subtree.
+func pruneExcept(n *html.Node, included map[*html.Node]bool, ancestors map[*html.Node]bool) {
+	for child := n.FirstChild; child != nil; {
+		next := child.NextSibling
+		switch {
+		case included[child]:
+		case ancestors[child]:
+			pruneExcept(child, included, ancestors)
+		default:
+			n.RemoveChild(child)
+		}
+		child = next
+	}
+}
+
+// findBody returns the body element of a parsed document, or nil.
+func findBody(doc *html.Node) *html.Node {
+	for root := doc.FirstChild; root != nil; root = root.NextSibling {
+		if root.Type != html.ElementNode || root.Data != "html" {
+			continue
+		}
+		for child := root.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type == html.ElementNode && child.Data == "body" {
+				return child
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package gomarkdownify
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestIncludeExclude tests filtering the document with CSS selectors
+func TestIncludeExclude(t *testing.T) {
+	page := `<html><head><title>Page</title></head><body>
+<nav><a href="/">Home</a></nav>
+<div class="ads"><p>Buy now</p></div>
+<article>
+<h1>Title</h1>
+<div class="content"><p>First <span aria-hidden="true">icon</span>paragraph.</p><div class="ads">Sponsored</div></div>
+<aside><div class="content"><p>Aside</p></div></aside>
+</article>
+<section id="comments"><h2>Comments</h2><p>Nice post</p></section>
+<footer>Copyright</footer>
+</body></html>`
+
+	tests := []struct {
+		name     string
+		options  []Option
+		expected string
+	}{
+		{
+			name:     "exclude",
+			options:  []Option{WithExclude("nav", ".ads", "#comments", "[aria-hidden=true]", "footer, aside", "title")},
+			expected: "# Title\n\nFirst paragraph.",
+		},
+		{
+			name:     "include",
+			options:  []Option{WithInclude("article > .content")},
+			expected: "First iconparagraph.\n\nSponsored",
+		},
+		{
+			name:     "include in document order",
+			options:  []Option{WithInclude("#comments p", "article h1", "article .content")},
+			expected: "# Title\n\nFirst iconparagraph.\n\nSponsored\n\nAside\n\nNice post",
+		},
+		{
+			name:     "exclude wins over include",
+			options:  []Option{WithInclude("article"), WithExclude(".ads", "[aria-hidden=true]", "aside")},
+			expected: "# Title\n\nFirst paragraph.",
+		},
+		{
+			name:     "nested matches are kept once",
+			options:  []Option{WithInclude("article", "article p")},
+			expected: "# Title\n\nFirst iconparagraph.\n\nSponsored\n\nAside",
+		},
+		{
+			name:     "no match",
+			options:  []Option{WithInclude("main")},
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := append([]Option{WithHeadingStyle(ATX), WithStripDocument(STRIP)}, tt.options...)
+			converter, err := NewConverter(options...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			result, err := converter.Convert(page)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+// TestIncludeKeepsHead tests that the head is kept for front matter and
+// that the analysis passes only see the included content
+func TestIncludeKeepsHead(t *testing.T) {
+	converter, err := NewConverter(
+		WithInclude("main"),
+		WithFrontMatter(YAML),
+		WithTOC(TOC_TOP, 2, 3),
+		WithHeadingStyle(ATX),
+		WithStripDocument(STRIP),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := converter.Convert(`<html><head><title>Guide</title></head><body>
+<div class="sidebar"><h2>Menu</h2></div>
+<main><h2>Install</h2><p>Run it.</p></main></body></html>`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(result, "title: Guide") {
+		t.Errorf("Expected the title in the front matter, got %q", result)
+	}
+	if strings.Contains(result, "Menu") {
+		t.Errorf("Expected the sidebar to be left out, got %q", result)
+	}
+	if !strings.Contains(result, "* [Install](#install)") {
+		t.Errorf("Expected the included heading in the table of contents, got %q", result)
+	}
+}
+
+// TestInvalidSelectors tests reporting selectors that do not parse
+func TestInvalidSelectors(t *testing.T) {
+	_, err := NewConverter(WithInclude("article"), WithExclude("nav", "div[", "p:unknown"))
+	var optionErr *OptionError
+	if !errors.As(err, &optionErr) || optionErr.Option != "Exclude[1]" {
+		t.Fatalf("Expected an Exclude[1] error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "Exclude[2]") {
+		t.Errorf("Expected every invalid selector to be reported, got %v", err)
+	}
+}
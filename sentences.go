@@ -0,0 +1,232 @@
+package gomarkdownify
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// sentenceAbbreviations lists lowercase abbreviations (without their final
+// period) that do not end a sentence when followed by a space.
+var sentenceAbbreviations = map[string]bool{
+	"al":     true,
+	"approx": true,
+	"cf":     true,
+	"dr":     true,
+	"e.g":    true,
+	"eg":     true,
+	"fig":    true,
+	"figs":   true,
+	"i.e":    true,
+	"ie":     true,
+	"inc":    true,
+	"jr":     true,
+	"ltd":    true,
+	"mr":     true,
+	"mrs":    true,
+	"ms":     true,
+	"no":     true,
+	"nos":    true,
+	"p":      true,
+	"pp":     true,
+	"prof":   true,
+	"sr":     true,
+	"st":     true,
+	"vol":    true,
+	"vs":     true,
+}
+
+// reflowSentences rewrites the prose in text so that each sentence starts on
+// a new line ("semantic line breaks").
+//
+// Consecutive prose lines are joined and then split again at sentence
+// boundaries, so soft line breaks from the source HTML do not survive. Lines
+// that belong to nested Markdown blocks (indented content, list items, quotes,
+// code fences, tables and headings), the lines inside fenced code blocks and
+// blank lines are left untouched, and a line ending in a hard line break
+// always ends the current run of prose.
+//
+// Parameters:
+//   - text: The Markdown text to reflow.
+//   - clauses: Whether to also break after clause punctuation (; and :).
+//
+// Returns:
+//   - The reflowed text.
+func reflowSentences(text string, clauses bool) string {
+	lines := strings.Split(text, "\n")
+	out := make([]string, 0, len(lines))
+	var run []string
+
+	flush := func() {
+		if len(run) == 0 {
+			return
+		}
+		out = append(out, splitSentences(strings.Join(run, " "), clauses)...)
+		run = run[:0]
+	}
+
+	fences := fencedRanges(text)
+	pos := 0
+	for _, line := range lines {
+		start := pos
+		pos += len(line) + 1
+		for len(fences) > 0 && fences[0][1] <= start {
+			fences = fences[1:]
+		}
+		inFence := len(fences) > 0 && fences[0][0] <= start
+
+		if inFence || !isProseLine(line) {
+			flush()
+			out = append(out, line)
+			continue
+		}
+
+		if isHardBreakLine(line) {
+			// Keep the trailing break marker intact
+			run = append(run, line)
+			flush()
+			continue
+		}
+
+		run = append(run, strings.TrimRight(line, " \t"))
+	}
+	flush()
+
+	return strings.Join(out, "\n")
+}
+
+// splitSentences splits a single line of prose into sentences.
+//
+// A sentence ends at ".", "!" or "?" (optionally followed by further
+// terminators and closing quotes, brackets or emphasis markers) when the
+// next character is a space. Periods after known abbreviations and single
+// letter initials, terminators followed by a lowercase word, and terminators
+// followed by text that would start a Markdown block ("- ", "1. ", "# ", "> "),
+// do not end a sentence. Decimals never split because they are not followed by a
+// space. Inline code spans and backslash-escaped characters are skipped.
+//
+// Parameters:
+//   - text: The prose to split.
+//   - clauses: Whether to also split after ";" and ":".
+//
+// Returns:
+//   - The sentences, with the whitespace between them removed.
+func splitSentences(text string, clauses bool) []string {
+	var sentences []string
+	start := 0
+	codeFence := 0
+
+	for i := 0; i < len(text); i++ {
+		ch := text[i]
+
+		// Skip over inline code spans, which are delimited by backtick runs
+		// of equal length
+		if ch == '`' {
+			j := i
+			for j < len(text) && text[j] == '`' {
+				j++
+			}
+			if codeFence == 0 {
+				codeFence = j - i
+			} else if codeFence == j-i {
+				codeFence = 0
+			}
+			i = j - 1
+			continue
+		}
+		if codeFence > 0 {
+			continue
+		}
+
+		// Escaped characters never end a sentence
+		if ch == '\\' {
+			i++
+			continue
+		}
+
+		isClause := ch == ';' || ch == ':'
+		if ch != '.' && ch != '!' && ch != '?' && !(clauses && isClause) {
+			continue
+		}
+
+		end := i + 1
+		if !isClause {
+			for end < len(text) && strings.IndexByte(".!?", text[end]) >= 0 {
+				end++
+			}
+		}
+		for end < len(text) && strings.IndexByte("\"')]*_", text[end]) >= 0 {
+			end++
+		}
+		if end >= len(text) || text[end] != ' ' {
+			continue
+		}
+
+		next := end
+		for next < len(text) && text[next] == ' ' {
+			next++
+		}
+		if next >= len(text) {
+			break
+		}
+
+		// A line starting with a list, quote or heading marker would turn the
+		// rest of the paragraph into that block
+		if reMarkdownBlockStart.MatchString(text[next:]) {
+			continue
+		}
+
+		if !isClause {
+			if ch == '.' && end == i+1 && isAbbreviation(text[start:i]) {
+				continue
+			}
+			if r, _ := utf8.DecodeRuneInString(text[next:]); unicode.IsLower(r) {
+				continue
+			}
+		}
+
+		sentences = append(sentences, text[start:end])
+		start = next
+		i = next - 1
+	}
+
+	return append(sentences, text[start:])
+}
+
+// isAbbreviation reports whether the last word of text (the text preceding a
+// period) is a known abbreviation or a single letter initial.
+func isAbbreviation(text string) bool {
+	word := text
+	if idx := strings.LastIndexAny(word, " \t("); idx >= 0 {
+		word = word[idx+1:]
+	}
+	word = strings.TrimLeft(word, "\"'[*_")
+	if word == "" {
+		return false
+	}
+
+	if utf8.RuneCountInString(word) == 1 {
+		r, _ := utf8.DecodeRuneInString(word)
+		return unicode.IsLetter(r)
+	}
+
+	return sentenceAbbreviations[strings.ToLower(word)]
+}
+
+// isProseLine reports whether a line of converted Markdown is part of a
+// paragraph that may be reflowed, as opposed to a blank line or the start
+// of a nested block.
+func isProseLine(line string) bool {
+	if strings.TrimSpace(line) == "" {
+		return false
+	}
+	if line[0] == ' ' || line[0] == '\t' {
+		return false
+	}
+	return !reMarkdownBlockStart.MatchString(line)
+}
+
+// isHardBreakLine reports whether a line ends with a Markdown hard line break.
+func isHardBreakLine(line string) bool {
+	return strings.HasSuffix(line, "  ") || strings.HasSuffix(line, "\\")
+}
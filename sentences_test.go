@@ -0,0 +1,100 @@
+package gomarkdownify
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestSplitSentences tests the sentence splitter used for semantic line breaks
+func TestSplitSentences(t *testing.T) {
+	tests := []struct {
+		text     string
+		clauses  bool
+		expected []string
+	}{
+		{"One. Two! Three? Four", false, []string{"One.", "Two!", "Three?", "Four"}},
+		{"Pi is 3.14. It is irrational.", false, []string{"Pi is 3.14.", "It is irrational."}},
+		{"See e.g. Mr. Smith. Then leave.", false, []string{"See e.g. Mr. Smith.", "Then leave."}},
+		{"J. R. R. Tolkien wrote it.", false, []string{"J. R. R. Tolkien wrote it."}},
+		{"Call `a. B` now. Done.", false, []string{"Call `a. B` now.", "Done."}},
+		{"He said \"Stop.\" Then left.", false, []string{"He said \"Stop.\"", "Then left."}},
+		{"Wait... what? Yes.", false, []string{"Wait... what?", "Yes."}},
+		{"It is **bold.** Next.", false, []string{"It is **bold.**", "Next."}},
+		{"First; second: third.", false, []string{"First; second: third."}},
+		{"First; second: third.", true, []string{"First;", "second:", "third."}},
+		{"Ratio 3:2 holds.", true, []string{"Ratio 3:2 holds."}},
+		{"I saw it. - Then a dash.", false, []string{"I saw it. - Then a dash."}},
+		{"I saw it. + Then a plus.", false, []string{"I saw it. + Then a plus."}},
+		{"I saw it. * Then a star.", false, []string{"I saw it. * Then a star."}},
+		{"I saw it. 1. Then a number.", false, []string{"I saw it. 1.", "Then a number."}},
+		{"I saw it. 2) Then a number.", false, []string{"I saw it. 2) Then a number."}},
+		{"I saw it. # Then a hash.", false, []string{"I saw it. # Then a hash."}},
+		{"I saw it. > Then a quote.", false, []string{"I saw it. > Then a quote."}},
+		{"Options: - a list.", true, []string{"Options: - a list."}},
+	}
+
+	for _, test := range tests {
+		result := splitSentences(test.text, test.clauses)
+		if !reflect.DeepEqual(result, test.expected) {
+			t.Errorf("splitSentences(%q, %v): Expected %q, got %q", test.text, test.clauses, test.expected, result)
+		}
+	}
+}
+
+// TestSemanticLineBreaks tests the SemanticLineBreaks option
+func TestSemanticLineBreaks(t *testing.T) {
+	options := DefaultOptions()
+	options.SemanticLineBreaks = true
+	options.StripDocument = STRIP
+
+	tests := []struct {
+		html     string
+		expected string
+	}{
+		{
+			html:     "<p>This is one sentence. This is\nanother one. And a third.</p>",
+			expected: "This is one sentence.\nThis is another one.\nAnd a third.",
+		},
+		{
+			html:     "<p>First line. Still first.<br>Second line. Still second.</p>",
+			expected: "First line.\nStill first.  \nSecond line.\nStill second.",
+		},
+		{
+			html:     "<ul><li>Item one. More about it.<ul><li>Nested. Also nested.</li></ul></li></ul>",
+			expected: "* Item one.\n  More about it.\n  + Nested.\n    Also nested.",
+		},
+		{
+			html:     "<ol><li>Step one. Then two.</li></ol>",
+			expected: "1. Step one.\n   Then two.",
+		},
+		{
+			html:     "<p>I saw it. - Then a dash. 3. Then a number. # Not a heading.</p>",
+			expected: "I saw it. - Then a dash. 3.\nThen a number. # Not a heading.",
+		},
+		{
+			html:     "<ul><li>Text. More.<pre>foo. Bar\nbaz. Qux</pre></li></ul>",
+			expected: "* Text.\n  More.\n\n  ```\n  foo. Bar\n  baz. Qux\n  ```",
+		},
+	}
+
+	for _, test := range tests {
+		result, err := Convert(test.html, options)
+		if err != nil {
+			t.Fatalf("Failed to convert HTML: %v", err)
+		}
+		if result != test.expected {
+			t.Errorf("Convert(%q): Expected %q, got %q", test.html, test.expected, result)
+		}
+	}
+
+	// Test clause breaks
+	options.SemanticLineBreakClauses = true
+	result, err := Convert("<p>Bring three things: a map; a torch. Go.</p>", options)
+	if err != nil {
+		t.Fatalf("Failed to convert HTML: %v", err)
+	}
+	expected := "Bring three things:\na map;\na torch.\nGo."
+	if result != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+}
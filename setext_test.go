@@ -0,0 +1,166 @@
+package gomarkdownify
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestSetextUnderlineWidth tests that setext underlines match the display width of the heading
+func TestSetextUnderlineWidth(t *testing.T) {
+	tests := []struct {
+		html     string
+		expected string
+	}{
+		{"<h1>Café</h1>", "Café\n===="},
+		{"<h1>Cafe\u0301</h1>", "Cafe\u0301\n===="},
+		{"<h1>日本語</h1>", "日本語\n======"},
+		{"<h2>🎉 Party</h2>", "🎉 Party\n--------"},
+		{"<h2>Ｆｕｌｌ</h2>", "Ｆｕｌｌ\n--------"},
+	}
+
+	for _, tt := range tests {
+		options := DefaultOptions()
+		options.StripDocument = STRIP
+		result, err := Convert(tt.html, options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result != tt.expected {
+			t.Errorf("%s: Expected %q, got %q", tt.html, tt.expected, result)
+		}
+	}
+}
+
+// TestHeadingLineBreaks tests line breaks inside headings for each heading style
+func TestHeadingLineBreaks(t *testing.T) {
+	tests := []struct {
+		name         string
+		html         string
+		style        string
+		newlineStyle string
+		expected     string
+	}{
+		{
+			name:     "ATX joins lines",
+			html:     "<h2>Part one<br>The beginning</h2>",
+			style:    ATX,
+			expected: "## Part one The beginning",
+		},
+		{
+			name:     "ATX closed joins lines",
+			html:     "<h3>Part one<br/><br/>The beginning</h3>",
+			style:    ATX_CLOSED,
+			expected: "### Part one The beginning ###",
+		},
+		{
+			name:     "Setext keeps lines",
+			html:     "<h1>Part one<br>The beginning</h1>",
+			style:    UNDERLINED,
+			expected: "Part one  \nThe beginning\n=============",
+		},
+		{
+			name:         "Setext keeps lines with backslash breaks",
+			html:         "<h2>日本<br>Part one</h2>",
+			style:        UNDERLINED,
+			newlineStyle: BACKSLASH,
+			expected:     "日本\\\nPart one\n--------",
+		},
+		{
+			name:     "Setext style falls back to ATX below level 2",
+			html:     "<h3>Part one<br>The beginning</h3>",
+			style:    UNDERLINED,
+			expected: "### Part one The beginning",
+		},
+		{
+			name:     "Trailing break",
+			html:     "<h1>Title<br></h1>",
+			style:    UNDERLINED,
+			expected: "Title\n=====",
+		},
+		{
+			name:     "Heading in a table cell",
+			html:     "<table><tr><th><h2>A<br>B</h2></th></tr></table>",
+			style:    UNDERLINED,
+			expected: "| A B |\n| --- |",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := DefaultOptions()
+			options.HeadingStyle = tt.style
+			options.StripDocument = STRIP
+			if tt.newlineStyle != "" {
+				options.NewlineStyle = tt.newlineStyle
+			}
+			result, err := Convert(tt.html, options)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+// TestHeadingLineBreaksNotConverted tests line breaks inside headings that are not converted
+func TestHeadingLineBreaksNotConverted(t *testing.T) {
+	tests := []struct {
+		name     string
+		html     string
+		options  []Option
+		expected string
+	}{
+		{
+			name:     "Stripped heading",
+			html:     "<h2>Line one<br>Line two</h2>",
+			options:  []Option{WithStrip("h2")},
+			expected: "Line one Line two",
+		},
+		{
+			name:     "Heading outside the converted tags",
+			html:     "<h2>Line one<br>Line two</h2><p>Text</p>",
+			options:  []Option{WithConvert("p", "br")},
+			expected: "Line one Line two\n\nText",
+		},
+		{
+			name:     "Heading converted as a callout",
+			html:     `<h2 class="note">Line one<br>Line two</h2>`,
+			options:  []Option{WithCallouts(true), WithCalloutRules(CalloutRule{Tag: "h2", Class: "note"})},
+			expected: "> [!NOTE]\n> Line one Line two",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			converter, err := NewConverter(append([]Option{WithStripDocument(STRIP)}, tt.options...)...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			result, err := converter.Convert(tt.html)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+// TestHeadingLineBreaksInLinks tests the text of links with line breaks inside headings
+func TestHeadingLineBreaksInLinks(t *testing.T) {
+	result, err := newTestConverter(t, DefaultOptions()).ConvertResult(`<h2><a href="x">a<br>b</a></h2>`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Link{{Text: "a b", URL: "x"}}
+	if !reflect.DeepEqual(result.Links, expected) {
+		t.Errorf("Expected links %+v, got %+v", expected, result.Links)
+	}
+	if strings.Contains(result.Markdown, headingLineBreak) {
+		t.Errorf("Unexpected line break marker in %q", result.Markdown)
+	}
+}
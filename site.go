@@ -0,0 +1,350 @@
+package gomarkdownify
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// SiteReport describes the outcome of converting a site directory.
+type SiteReport struct {
+	// Pages lists the converted pages, as paths relative to the source directory
+	Pages []string
+	// Assets lists the copied images and linked files, as paths relative to the
+	// source directory
+	Assets []string
+	// BrokenLinks lists the internal links and images whose target does not exist
+	BrokenLinks []BrokenLink
+	// Failed lists the pages that could not be converted or written
+	Failed []PageError
+}
+
+// PageError is the error that stopped a page of a site from being converted.
+type PageError struct {
+	// Page is the page, relative to the source directory
+	Page string
+	// Err is the error, such as a *LimitError for a page over the limits
+	Err error
+}
+
+// Error returns the error message, prefixed with the page.
+func (e PageError) Error() string {
+	return e.Page + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e PageError) Unwrap() error {
+	return e.Err
+}
+
+// BrokenLink is an internal link or image whose target does not exist.
+type BrokenLink struct {
+	// Page is the page containing the link, relative to the source directory
+	Page string
+	// URL is the link target as written in the HTML
+	URL string
+}
+
+// ConvertSite converts a directory tree of HTML files to Markdown files.
+//
+// Every .html, .htm and .xhtml file below srcDir is converted with the
+// converter's options and written to the same relative path below dstDir
+// with a .md extension. Links between pages are rewritten to point at the
+// Markdown files, and local images and other linked files are copied to
+// dstDir so their relative paths keep working. Root-relative URLs ("/docs/guide.html") are
+// resolved against srcDir and rewritten as relative paths. Links and images
+// pointing at files that do not exist are reported in the returned report
+// and left unchanged. A page that fails to convert, for example because it
+// exceeds MaxInputSize or MaxDepth, is recorded in the report's Failed list
+// and the other pages are still converted; the returned error is only set
+// when srcDir cannot be read. dstDir may be srcDir, in which case the Markdown files
+// are written next to the pages and the assets are left in place.
+//
+// Example:
+//
+//	converter, err := gomarkdownify.NewConverter(gomarkdownify.DefaultOptions())
+//	if err != nil {
+//	    // handle error
+//	}
+//	report, err := converter.ConvertSite("public", "docs")
+//	if err != nil {
+//	    // handle error
+//	}
+//	for _, link := range report.BrokenLinks {
+//	    fmt.Printf("%s: broken link %s\n", link.Page, link.URL)
+//	}
+func (c *Converter) ConvertSite(srcDir string, dstDir string) (*SiteReport, error) {
+	srcDir, err := filepath.Abs(srcDir)
+	if err != nil {
+		return nil, err
+	}
+	dstDir, err = filepath.Abs(dstDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var pages []string
+	err = filepath.WalkDir(srcDir, func(p string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() || !isHTMLPath(p) {
+			return err
+		}
+		rel, err := filepath.Rel(srcDir, p)
+		if err != nil {
+			return err
+		}
+		pages = append(pages, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	site := &siteConverter{
+		converter: c,
+		srcDir:    srcDir,
+		dstDir:    dstDir,
+		report:    &SiteReport{},
+		copied:    make(map[string]bool),
+	}
+	for _, page := range pages {
+		if err := site.convertPage(page); err != nil {
+			site.report.Failed = append(site.report.Failed, PageError{Page: page, Err: err})
+		}
+	}
+
+	return site.report, nil
+}
+
+// siteConverter holds the state of a ConvertSite call.
+type siteConverter struct {
+	converter *Converter
+	srcDir    string
+	dstDir    string
+	report    *SiteReport
+	// copied holds the assets copied so far, by path relative to srcDir
+	copied map[string]bool
+}
+
+// convertPage converts a single page, given as a slash-separated path
+// relative to the source directory.
+func (s *siteConverter) convertPage(page string) error {
+	data, err := os.ReadFile(filepath.Join(s.srcDir, filepath.FromSlash(page)))
+	if err != nil {
+		return err
+	}
+	doc, err := html.Parse(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	if err := s.rewriteURLs(doc, page); err != nil {
+		return err
+	}
+
+	var rendered bytes.Buffer
+	if err := html.Render(&rendered, doc); err != nil {
+		return err
+	}
+	markdown, err := s.converter.Convert(rendered.String())
+	if err != nil {
+		return err
+	}
+
+	output := filepath.Join(s.dstDir, filepath.FromSlash(markdownPath(page)))
+	if err := os.MkdirAll(filepath.Dir(output), 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(output, []byte(strings.TrimRight(markdown, "\n")+"\n"), 0o644); err != nil {
+		return err
+	}
+
+	s.report.Pages = append(s.report.Pages, page)
+	return nil
+}
+
+// rewriteURLs rewrites the links and images of a page for the converted site
+// and copies the local files they use.
+func (s *siteConverter) rewriteURLs(n *html.Node, page string) error {
+	if n.Type == html.ElementNode {
+		var err error
+		switch n.Data {
+		case "a":
+			err = s.rewriteLink(n, page)
+		case "img":
+			err = s.rewriteImage(n, page)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if err := s.rewriteURLs(child, page); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rewriteLink points a link to another page at its Markdown file. Other
+// local files, such as downloads, are copied to the output directory.
+func (s *siteConverter) rewriteLink(n *html.Node, page string) error {
+	href := getAttr(n, "href")
+	u, target, ok := resolveSiteURL(href, page)
+	if !ok {
+		return nil
+	}
+
+	info, err := os.Stat(filepath.Join(s.srcDir, filepath.FromSlash(target)))
+	if err == nil && info.IsDir() {
+		target, err = s.findIndex(target)
+	}
+	if err != nil {
+		s.report.BrokenLinks = append(s.report.BrokenLinks, BrokenLink{Page: page, URL: href})
+		return nil
+	}
+
+	if isHTMLPath(target) {
+		target = markdownPath(target)
+	} else if err := s.copyAsset(target); err != nil {
+		return err
+	}
+	setAttr(n, "href", relativeSiteURL(page, target, u.Fragment))
+	return nil
+}
+
+// rewriteImage copies a local image to the output directory, and makes
+// root-relative image URLs relative.
+func (s *siteConverter) rewriteImage(n *html.Node, page string) error {
+	src := getAttr(n, "src")
+	u, target, ok := resolveSiteURL(src, page)
+	if !ok {
+		return nil
+	}
+
+	if info, err := os.Stat(filepath.Join(s.srcDir, filepath.FromSlash(target))); err != nil || info.IsDir() {
+		s.report.BrokenLinks = append(s.report.BrokenLinks, BrokenLink{Page: page, URL: src})
+		return nil
+	}
+
+	if err := s.copyAsset(target); err != nil {
+		return err
+	}
+
+	if strings.HasPrefix(u.Path, "/") {
+		setAttr(n, "src", relativeSiteURL(page, target, u.Fragment))
+	}
+	return nil
+}
+
+// copyAsset copies a file of the site to the same path in the output
+// directory, once.
+func (s *siteConverter) copyAsset(target string) error {
+	if s.copied[target] {
+		return nil
+	}
+	source := filepath.Join(s.srcDir, filepath.FromSlash(target))
+	if err := copyFile(source, filepath.Join(s.dstDir, filepath.FromSlash(target))); err != nil {
+		return err
+	}
+	s.copied[target] = true
+	s.report.Assets = append(s.report.Assets, target)
+	return nil
+}
+
+// findIndex returns the index page of a directory in the site.
+func (s *siteConverter) findIndex(dir string) (string, error) {
+	var err error
+	for _, name := range []string{"index.html", "index.htm"} {
+		index := path.Join(dir, name)
+		if _, err = os.Stat(filepath.Join(s.srcDir, filepath.FromSlash(index))); err == nil {
+			return index, nil
+		}
+	}
+	return "", err
+}
+
+// resolveSiteURL resolves a URL found on a page to a slash-separated path
+// relative to the site root. It returns false for external URLs, URLs
+// without a path (such as "#section") and paths outside the site.
+func resolveSiteURL(raw string, page string) (*url.URL, string, bool) {
+	if raw == "" {
+		return nil, "", false
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme != "" || u.Host != "" || u.Opaque != "" || u.Path == "" {
+		return nil, "", false
+	}
+
+	var target string
+	if strings.HasPrefix(u.Path, "/") {
+		target = path.Clean(strings.TrimPrefix(u.Path, "/"))
+	} else {
+		target = path.Join(path.Dir(page), u.Path)
+	}
+	if target == ".." || strings.HasPrefix(target, "../") {
+		return nil, "", false
+	}
+	return u, target, true
+}
+
+// relativeSiteURL returns the URL of target relative to the directory of page.
+func relativeSiteURL(page string, target string, fragment string) string {
+	rel, err := filepath.Rel(filepath.FromSlash(path.Dir(page)), filepath.FromSlash(target))
+	if err != nil {
+		rel = target
+	}
+	u := url.URL{Path: filepath.ToSlash(rel), Fragment: fragment}
+	return u.String()
+}
+
+// markdownPath replaces the extension of an HTML path with .md.
+func markdownPath(p string) string {
+	return strings.TrimSuffix(p, path.Ext(p)) + ".md"
+}
+
+// isHTMLPath reports whether a path has an HTML file extension.
+func isHTMLPath(p string) bool {
+	switch strings.ToLower(path.Ext(filepath.ToSlash(p))) {
+	case ".html", ".htm", ".xhtml":
+		return true
+	}
+	return false
+}
+
+// copyFile copies a file, creating the parent directories of dst. Nothing is
+// copied when dst is src, which would truncate it.
+func copyFile(src string, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	srcInfo, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	if dstInfo, err := os.Stat(dst); err == nil && os.SameFile(srcInfo, dstInfo) {
+		return nil
+	}
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,141 @@
+package gomarkdownify
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestConvertSite tests converting a directory of HTML pages
+func TestConvertSite(t *testing.T) {
+	src := t.TempDir()
+	dst := t.TempDir()
+	files := map[string]string{
+		"index.html": `<h1>Home</h1><p><a href="docs/guide.html#install">Guide</a> ` +
+			`<a href="docs/">Docs</a> <a href="missing.html">Missing</a> ` +
+			`<a href="https://example.com/a.html">External</a> <a href="#top">Top</a></p>` +
+			`<img src="img/logo.png" alt="Logo">`,
+		"docs/index.html": `<p><a href="../index.html">Up</a> <a href="/docs/guide.html">Guide</a></p>`,
+		"docs/guide.html": `<p><img src="/img/logo.png" alt="Logo"> <img src="shot.png" alt="Shot"> ` +
+			`<a href="files/manual.pdf">Manual</a></p>`,
+		"docs/files/manual.pdf": "%PDF",
+		"img/logo.png":          "PNG",
+		"img/unused.png":        "PNG",
+	}
+	for name, content := range files {
+		path := filepath.Join(src, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	options := DefaultOptions()
+	options.HeadingStyle = ATX
+	report, err := newTestConverter(t, options).ConvertSite(src, dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"index.md":      "# Home\n\n[Guide](docs/guide.md#install) [Docs](docs/index.md) [Missing](missing.html) [External](https://example.com/a.html) [Top](#top)\n\n![Logo](img/logo.png)\n",
+		"docs/index.md": "[Up](../index.md) [Guide](guide.md)\n",
+		"docs/guide.md": "![Logo](../img/logo.png) ![Shot](shot.png) [Manual](files/manual.pdf)\n",
+	}
+	for name, content := range expected {
+		data, err := os.ReadFile(filepath.Join(dst, filepath.FromSlash(name)))
+		if err != nil {
+			t.Errorf("Expected %s to be written: %v", name, err)
+			continue
+		}
+		if string(data) != content {
+			t.Errorf("%s: Expected %q, got %q", name, content, string(data))
+		}
+	}
+
+	if pages := []string{"docs/guide.html", "docs/index.html", "index.html"}; !reflect.DeepEqual(report.Pages, pages) {
+		t.Errorf("Expected pages %q, got %q", pages, report.Pages)
+	}
+	if assets := []string{"img/logo.png", "docs/files/manual.pdf"}; !reflect.DeepEqual(report.Assets, assets) {
+		t.Errorf("Expected assets %q, got %q", assets, report.Assets)
+	}
+	if _, err := os.Stat(filepath.Join(dst, "img", "unused.png")); err == nil {
+		t.Errorf("Expected unused images not to be copied")
+	}
+
+	broken := []BrokenLink{
+		{Page: "docs/guide.html", URL: "shot.png"},
+		{Page: "index.html", URL: "missing.html"},
+	}
+	if !reflect.DeepEqual(report.BrokenLinks, broken) {
+		t.Errorf("Expected broken links %+v, got %+v", broken, report.BrokenLinks)
+	}
+}
+
+// TestConvertSiteInPlace tests converting a site into its own directory
+func TestConvertSiteInPlace(t *testing.T) {
+	src := t.TempDir()
+	if err := os.WriteFile(filepath.Join(src, "index.html"), []byte(`<p><img src="a.png" alt="A"></p>`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "a.png"), []byte("PNG content"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The same directory written differently
+	report, err := newTestConverter(t, DefaultOptions()).ConvertSite(src, filepath.Join(src, "."))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if data, err := os.ReadFile(filepath.Join(src, "a.png")); err != nil || string(data) != "PNG content" {
+		t.Errorf("Expected the asset to be kept, got %q, %v", data, err)
+	}
+	if data, err := os.ReadFile(filepath.Join(src, "index.md")); err != nil || string(data) != "![A](a.png)\n" {
+		t.Errorf("Expected the page to be written next to the source, got %q, %v", data, err)
+	}
+	if assets := []string{"a.png"}; !reflect.DeepEqual(report.Assets, assets) {
+		t.Errorf("Expected assets %q, got %q", assets, report.Assets)
+	}
+}
+
+// TestConvertSiteFailedPage tests that a page over the limits does not stop the other pages
+func TestConvertSiteFailedPage(t *testing.T) {
+	src := t.TempDir()
+	dst := t.TempDir()
+	files := map[string]string{
+		"a.html":   `<p><a href="big.html">Big</a></p>`,
+		"big.html": `<p>` + strings.Repeat("text ", 100) + `</p>`,
+		"c.html":   `<p><a href="missing.html">Missing</a></p>`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(src, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	options := DefaultOptions()
+	options.MaxInputSize = 100
+	report, err := newTestConverter(t, options).ConvertSite(src, dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if pages := []string{"a.html", "c.html"}; !reflect.DeepEqual(report.Pages, pages) {
+		t.Errorf("Expected pages %q, got %q", pages, report.Pages)
+	}
+	if len(report.Failed) != 1 || report.Failed[0].Page != "big.html" || !errors.Is(report.Failed[0], ErrInputTooLarge) {
+		t.Errorf("Expected big.html to fail with ErrInputTooLarge, got %+v", report.Failed)
+	}
+	if len(report.BrokenLinks) != 1 || report.BrokenLinks[0].Page != "c.html" {
+		t.Errorf("Expected the broken link of c.html, got %+v", report.BrokenLinks)
+	}
+	if _, err := os.Stat(filepath.Join(dst, "c.md")); err != nil {
+		t.Errorf("Expected c.md to be written: %v", err)
+	}
+}
@@ -0,0 +1,146 @@
+package gomarkdownify
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// slugify converts heading text to a GitHub-style anchor slug.
+//
+// The text is lowercased, characters other than letters, digits, spaces,
+// hyphens and underscores are removed, and spaces become hyphens.
+//
+// Parameters:
+//   - text: The plain heading text.
+//
+// Returns:
+//   - The slug for the heading.
+func slugify(text string) string {
+	var slug strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(text)) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_':
+			slug.WriteRune(r)
+		case r == ' ':
+			slug.WriteRune('-')
+		}
+	}
+	return slug.String()
+}
+
+// slugifyPandoc converts heading text to an identifier the way Pandoc's
+// auto_identifiers extension does.
+//
+// Characters other than letters, digits, underscores, hyphens and periods
+// are removed, whitespace becomes hyphens, everything before the first
+// letter is dropped, and "section" is used when nothing is left.
+func slugifyPandoc(text string) string {
+	var slug strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(text)) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-' || r == '.':
+			slug.WriteRune(r)
+		case unicode.IsSpace(r):
+			slug.WriteRune('-')
+		}
+	}
+	return trimToFirstLetter(slug.String())
+}
+
+// slugifyKramdown converts heading text to an identifier the way kramdown's
+// auto_ids option does.
+//
+// Characters other than ASCII letters, digits, spaces and hyphens are
+// removed, everything before the first letter is dropped, spaces become
+// hyphens, and "section" is used when nothing is left.
+func slugifyKramdown(text string) string {
+	var slug strings.Builder
+	for _, r := range text {
+		switch {
+		case r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-':
+			slug.WriteRune(unicode.ToLower(r))
+		case r == ' ':
+			slug.WriteRune('-')
+		}
+	}
+	return trimToFirstLetter(slug.String())
+}
+
+// slugifyMkDocs converts heading text to an identifier the way the
+// Python-Markdown toc extension used by MkDocs does.
+//
+// The text is decomposed and reduced to ASCII, characters other than word
+// characters, whitespace and hyphens are removed, and runs of whitespace and
+// hyphens become a single hyphen.
+func slugifyMkDocs(text string) string {
+	var slug strings.Builder
+	pendingHyphen := false
+	for _, r := range strings.ToLower(strings.TrimSpace(norm.NFKD.String(text))) {
+		switch {
+		case r > unicode.MaxASCII:
+			continue
+		case r == '-' || unicode.IsSpace(r):
+			pendingHyphen = slug.Len() > 0
+		case r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '_':
+			if pendingHyphen {
+				slug.WriteRune('-')
+				pendingHyphen = false
+			}
+			slug.WriteRune(r)
+		}
+	}
+	return slug.String()
+}
+
+// trimToFirstLetter drops everything before the first letter of an
+// identifier, returning "section" if no letter is left.
+func trimToFirstLetter(slug string) string {
+	idx := strings.IndexFunc(slug, unicode.IsLetter)
+	if idx < 0 {
+		return "section"
+	}
+	return slug[idx:]
+}
+
+// slugger generates unique slugs within a document, numbering repeated
+// slugs the way the targeted flavor does ("intro", "intro-1", "intro-2"
+// on GitHub, "intro_1" in MkDocs).
+type slugger struct {
+	slugify   func(string) string
+	separator string
+	seen      map[string]int
+}
+
+// newSlugger creates a slugger for a new document in the given flavor.
+// Flavors without automatic heading identifiers use GitHub-style slugs.
+func newSlugger(flavor string) *slugger {
+	s := &slugger{slugify: slugify, separator: "-", seen: make(map[string]int)}
+	switch flavor {
+	case PANDOC:
+		s.slugify = slugifyPandoc
+	case KRAMDOWN:
+		s.slugify = slugifyKramdown
+	case MKDOCS:
+		s.slugify = slugifyMkDocs
+		s.separator = "_"
+	}
+	return s
+}
+
+// slug returns the unique slug for the given heading text.
+func (s *slugger) slug(text string) string {
+	base := s.slugify(text)
+	slug := base
+	for {
+		if _, ok := s.seen[slug]; !ok {
+			break
+		}
+		s.seen[base]++
+		slug = base + s.separator + strconv.Itoa(s.seen[base])
+	}
+	s.seen[slug] = 0
+	return slug
+}
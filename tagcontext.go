@@ -0,0 +1,92 @@
+package gomarkdownify
+
+import (
+	"slices"
+
+	"golang.org/x/net/html"
+)
+
+// contextFlags is a set of facts about the ancestors of a node.
+type contextFlags uint16
+
+const (
+	// inPre is set inside <pre>
+	inPre contextFlags = 1 << iota
+	// inParagraph is set inside <p>
+	inParagraph
+	// inListItem is set inside <li>
+	inListItem
+	// inTableCell is set inside <td> and <th>
+	inTableCell
+	// inTableHead is set inside <thead>
+	inTableHead
+	// inHeading is set inside <h1> to <h6>
+	inHeading
+	// inH1 is set inside <h1>
+	inH1
+	// inInline is set where block content must stay on one line: inside
+	// headings and table cells
+	inInline
+	// inNoFormat is set where text is not escaped or formatted: inside <pre>,
+	// <code>, <kbd> and <samp>
+	inNoFormat
+	// inInlineElement is set inside inline formatting elements and links
+	inInlineElement
+	// inKeepInlineImages is set inside the tags listed in KeepInlineImagesIn
+	inKeepInlineImages
+)
+
+// tagContext describes the ancestors of the node being converted. It is
+// passed by value down the tree, so entering an element does not allocate,
+// and questions about the ancestors take constant time whatever the depth.
+type tagContext struct {
+	flags contextFlags
+	// ulDepth is the number of <ul> ancestors
+	ulDepth int
+	// item is the position of the node among the <li> children of its
+	// parent, starting at 0
+	item int
+}
+
+// has reports whether any of the flags is set.
+func (t tagContext) has(flags contextFlags) bool {
+	return t.flags&flags != 0
+}
+
+// enter returns the context of the children of the element n.
+func (t tagContext) enter(n *html.Node, keepInlineImagesIn []string) tagContext {
+	child := tagContext{flags: t.flags, ulDepth: t.ulDepth}
+
+	switch n.Data {
+	case "pre":
+		child.flags |= inPre | inNoFormat
+	case "code":
+		child.flags |= inNoFormat | inInlineElement
+	case "kbd", "samp":
+		child.flags |= inNoFormat
+	case "p":
+		child.flags |= inParagraph
+	case "li":
+		child.flags |= inListItem
+	case "td", "th":
+		child.flags |= inTableCell | inInline
+	case "thead":
+		child.flags |= inTableHead
+	case "ul":
+		child.ulDepth++
+	case "a", "img", "b", "strong", "i", "em", "del", "s", "sub", "sup":
+		child.flags |= inInlineElement
+	default:
+		if reHTMLHeading.MatchString(n.Data) {
+			child.flags |= inHeading | inInline
+			if n.Data == "h1" {
+				child.flags |= inH1
+			}
+		}
+	}
+
+	if slices.Contains(keepInlineImagesIn, n.Data) {
+		child.flags |= inKeepInlineImages
+	}
+	return child
+}
@@ -0,0 +1,78 @@
+package gomarkdownify
+
+import (
+	"testing"
+
+	"golang.org/x/net/html"
+)
+
+// TestTagContextEnter tests the context passed to the children of elements
+func TestTagContextEnter(t *testing.T) {
+	element := func(tag string) *html.Node {
+		return &html.Node{Type: html.ElementNode, Data: tag}
+	}
+
+	var root tagContext
+	cell := root.enter(element("table"), nil).enter(element("thead"), nil).enter(element("tr"), nil).enter(element("th"), []string{"th"})
+	if !cell.has(inTableCell) || !cell.has(inTableHead) || !cell.has(inInline) || !cell.has(inKeepInlineImages) {
+		t.Errorf("Expected table cell flags, got %b", cell.flags)
+	}
+	if cell.has(inHeading | inNoFormat | inListItem) {
+		t.Errorf("Unexpected flags %b", cell.flags)
+	}
+
+	code := root.enter(element("h1"), nil).enter(element("code"), nil)
+	if !code.has(inHeading) || !code.has(inH1) || !code.has(inNoFormat) || !code.has(inInlineElement) {
+		t.Errorf("Expected heading and code flags, got %b", code.flags)
+	}
+
+	list := root.enter(element("ul"), nil).enter(element("li"), nil).enter(element("ul"), nil)
+	if list.ulDepth != 2 || !list.has(inListItem) {
+		t.Errorf("Expected two lists deep in a list item, got %+v", list)
+	}
+
+	// The position among list items is not inherited
+	list.item = 3
+	if child := list.enter(element("li"), nil); child.item != 0 {
+		t.Errorf("Expected item 0, got %d", child.item)
+	}
+}
+
+// TestListItemContext tests list items numbered and bulleted from their context
+func TestListItemContext(t *testing.T) {
+	tests := []struct {
+		name     string
+		html     string
+		expected string
+	}{
+		{
+			name:     "ordered list with start",
+			html:     "<ol start=\"3\"><li>a</li><!-- note --> <li>b</li><li>c</li></ol>",
+			expected: "3. a\n4. b\n5. c",
+		},
+		{
+			name:     "nested bullets",
+			html:     "<ul><li>a<ul><li>b<ul><li>c<ul><li>d</li></ul></li></ul></li></ul></li></ul>",
+			expected: "* a\n  + b\n    - c\n      * d",
+		},
+		{
+			name:     "item outside a list",
+			html:     "<li>a</li>",
+			expected: "* a",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := DefaultOptions()
+			options.StripDocument = STRIP
+			result, err := Convert(tt.html, options)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
@@ -17,12 +17,12 @@ import (
 // Parameters:
 //   - n: The HTML node representing the anchor element
 //   - text: The text content of the anchor element
-//   - parentTags: A list of parent tag names, used for context-aware conversion
+//   - parents: The context of the ancestors, used for context-aware conversion
 //
 // Returns:
 //   - A string containing the Markdown representation of the link
-func (c *Converter) convertA(n *html.Node, text string, parentTags []string) string {
-	if contains(parentTags, "_noformat") {
+func (c *Converter) convertA(n *html.Node, text string, parents tagContext) string {
+	if parents.has(inNoFormat) {
 		return text
 	}
 
@@ -34,6 +34,16 @@ func (c *Converter) convertA(n *html.Node, text string, parentTags []string) str
 	href := getAttr(n, "href")
 	title := getAttr(n, "title")
 
+	if c.options.RewriteAnchorLinks {
+		href = c.rewriteAnchorLink(href)
+	}
+
+	if href != "" {
+		// Heading line breaks are only resolved later by convertH
+		linkText := strings.ReplaceAll(text, headingLineBreak, " ")
+		c.state.links = append(c.state.links, Link{Text: linkText, URL: href, Title: title})
+	}
+
 	// For URLs that match their link text, use the shortcut syntax
 	if c.options.Autolinks && text == href && title == "" && !c.options.DefaultTitle {
 		return "<" + href + ">"
@@ -50,9 +60,7 @@ func (c *Converter) convertA(n *html.Node, text string, parentTags []string) str
 	}
 
 	// Don't add newlines around links in inline contexts
-	if !contains(parentTags, "_inline_element") &&
-		!contains(parentTags, "p") && !contains(parentTags, "li") &&
-		!contains(parentTags, "td") && !contains(parentTags, "th") {
+	if !parents.has(inInlineElement | inParagraph | inListItem | inTableCell) {
 		// For standalone links, return without newlines
 		if href != "" {
 			return "[" + text + "](" + href + titlePart + ")"
@@ -67,16 +75,22 @@ func (c *Converter) convertA(n *html.Node, text string, parentTags []string) str
 }
 
 // convertB converts <b> and <strong> tags to Markdown strong emphasis
-func (c *Converter) convertB(n *html.Node, text string, parentTags []string) string {
-	markup := strings.Repeat(c.options.StrongEmSymbol, 2)
-	return c.abstractInlineConversion(n, text, parentTags, markup)
+func (c *Converter) convertB(n *html.Node, text string, parents tagContext) string {
+	markup := strings.Repeat(c.strongEmSymbol(), 2)
+	return c.abstractInlineConversion(n, text, parents, markup)
 }
 
-// convertBlockquote converts <blockquote> tags to Markdown blockquotes
-func (c *Converter) convertBlockquote(n *html.Node, text string, parentTags []string) string {
+// convertBlockquote converts <blockquote> tags to Markdown blockquotes.
+//
+// The children are rendered first, so the text holds complete Markdown
+// blocks: paragraphs, lists, code blocks and nested blockquotes. Every line
+// of these blocks is prefixed with "> ", and the blank lines separating them
+// become ">" so the blocks stay inside the quote. Nested blockquotes are
+// already prefixed, which gives them one more marker per level.
+func (c *Converter) convertBlockquote(n *html.Node, text string, parents tagContext) string {
 	text = strings.TrimSpace(text)
 
-	if contains(parentTags, "_inline") {
+	if parents.has(inInline) {
 		return " " + text + " "
 	}
 
@@ -84,56 +98,129 @@ func (c *Converter) convertBlockquote(n *html.Node, text string, parentTags []st
 		return "\n"
 	}
 
-	// Special cases for TestBlockquote
-	if text == "Hello" {
-		return "\n> Hello\n\n"
-	} else if strings.Contains(text, "And she was like") && strings.Contains(text, "Hello") {
-		return "\n> And she was like\n> > Hello\n\n"
-	}
+	return "\n" + quoteLines(text) + "\n\n"
+}
 
-	// Indent each line with a blockquote marker
-	lines := strings.Split(text, "\n")
-	for i, line := range lines {
-		if line == "" {
-			lines[i] = ">"
-		} else {
-			lines[i] = "> " + line
+// convertBr converts <br> tags to Markdown line breaks
+func (c *Converter) convertBr(n *html.Node, text string, parents tagContext) string {
+	if parents.has(inInline) {
+		// Headings decide how to render their line breaks themselves
+		if parents.has(inHeading) && !parents.has(inTableCell) {
+			return headingLineBreak
 		}
+		return " "
 	}
 
-	return "\n" + strings.Join(lines, "\n") + "\n\n"
+	return c.hardLineBreak()
 }
 
-// convertBr converts <br> tags to Markdown line breaks
-func (c *Converter) convertBr(n *html.Node, text string, parentTags []string) string {
-	if contains(parentTags, "_inline") {
-		return " "
+// strongEmSymbol returns the configured StrongEmSymbol, or ASTERISK when it is empty.
+func (c *Converter) strongEmSymbol() string {
+	if c.options.StrongEmSymbol == "" {
+		return ASTERISK
 	}
+	return c.options.StrongEmSymbol
+}
 
+// hardLineBreak returns a Markdown hard line break in the configured NewlineStyle.
+func (c *Converter) hardLineBreak() string {
 	if c.options.NewlineStyle == BACKSLASH {
 		return "\\\n"
-	} else {
-		return "  \n"
 	}
+	return "  \n"
 }
 
 // convertCode converts <code>, <kbd>, and <samp> tags to Markdown code
-func (c *Converter) convertCode(n *html.Node, text string, parentTags []string) string {
-	if contains(parentTags, "pre") {
+func (c *Converter) convertCode(n *html.Node, text string, parents tagContext) string {
+	if parents.has(inPre) {
 		return text
 	}
 
-	return c.abstractInlineConversion(n, text, parentTags, "`")
+	return c.abstractInlineConversion(n, text, parents, "`")
+}
+
+// convertDd converts <dd> tags to definition list definitions.
+//
+// For flavors with definition list syntax the definition is introduced by
+// ": " and continuation lines are indented by four spaces. Otherwise the
+// definition is rendered as a separate paragraph indented by two spaces.
+func (c *Converter) convertDd(n *html.Node, text string, parents tagContext) string {
+	text = strings.TrimSpace(text)
+
+	if parents.has(inInline) {
+		return " " + text + " "
+	}
+
+	if text == "" {
+		return ""
+	}
+
+	if supportsDefinitionLists(c.flavor()) {
+		return ": " + indentLines(text, "    ") + "\n"
+	}
+
+	return "\n  " + indentLines(text, "  ") + "\n"
 }
 
 // convertDel converts <del> and <s> tags to Markdown strikethrough
-func (c *Converter) convertDel(n *html.Node, text string, parentTags []string) string {
-	return c.abstractInlineConversion(n, text, parentTags, "~~")
+func (c *Converter) convertDel(n *html.Node, text string, parents tagContext) string {
+	return c.abstractInlineConversion(n, text, parents, "~~")
+}
+
+// convertDetails converts <details> collapsible sections according to the
+// Details option.
+//
+// DETAILS_HTML keeps the <details> and <summary> elements, with the body
+// converted to Markdown between blank lines so it is rendered as Markdown
+// (as GitHub does). DETAILS_CALLOUT turns the section into a callout titled
+// with the summary, which is collapsible for OBSIDIAN and MKDOCS.
+// DETAILS_HEADING turns the summary into a heading one level below the
+// preceding heading, followed by the body. Its level and anchor come from
+// the heading pre-pass, see walkHeadings.
+func (c *Converter) convertDetails(n *html.Node, text string, parents tagContext) string {
+	text = strings.TrimSpace(text)
+
+	// The summary was rendered separately by convertSummary
+	var summaryNode *html.Node
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.ElementNode && child.Data == "summary" {
+			summaryNode = child
+			break
+		}
+	}
+	summary, summaryText := "Details", "Details"
+	if summaryNode != nil {
+		if plain := strings.TrimSpace(reAllWhitespace.ReplaceAllString(textContent(summaryNode), " ")); plain != "" {
+			summary, summaryText = plain, strings.TrimSpace(c.state.summaries[summaryNode])
+		}
+	}
+
+	if parents.has(inInline) {
+		return " " + strings.TrimSpace(summaryText+" "+text) + " "
+	}
+
+	switch c.options.Details {
+	case DETAILS_CALLOUT:
+		return c.convertCallout(callout{kind: "note", title: summary, collapsible: true, open: hasAttr(n, "open")}, text, parents)
+	case DETAILS_HEADING:
+		heading := c.convertH(1, detailsHeading(n), summaryText, parents)
+		return heading + "\n\n" + text + "\n\n"
+	default:
+		open := "<details>"
+		if hasAttr(n, "open") {
+			open = "<details open>"
+		}
+		body := ""
+		if text != "" {
+			body = "\n\n" + text
+		}
+		return "\n\n" + open + "\n<summary>" + html.EscapeString(summary) + "</summary>" + body + "\n\n</details>\n\n"
+	}
 }
 
 // convertDiv converts <div>, <article>, and <section> tags
-func (c *Converter) convertDiv(n *html.Node, text string, parentTags []string) string {
-	if contains(parentTags, "_inline") {
+func (c *Converter) convertDiv(n *html.Node, text string, parents tagContext) string {
+	if parents.has(inInline) {
 		return " " + strings.TrimSpace(text) + " "
 	}
 
@@ -145,33 +232,115 @@ func (c *Converter) convertDiv(n *html.Node, text string, parentTags []string) s
 	return "\n\n" + text
 }
 
+// convertDl converts <dl> tags to definition lists.
+//
+// The terms and definitions are rendered by convertDt and convertDd; this
+// function only separates the list from the surrounding blocks.
+func (c *Converter) convertDl(n *html.Node, text string, parents tagContext) string {
+	text = strings.TrimSpace(text)
+
+	if parents.has(inInline) {
+		return " " + text + " "
+	}
+
+	if text == "" {
+		return ""
+	}
+
+	return "\n\n" + text + "\n\n"
+}
+
+// convertDt converts <dt> tags to definition list terms.
+//
+// A term that starts a new group (it does not directly follow another term)
+// is separated from the previous group by a blank line. Without definition
+// list support, terms are rendered in strong emphasis and consecutive terms
+// are joined with hard line breaks.
+func (c *Converter) convertDt(n *html.Node, text string, parents tagContext) string {
+	text = strings.TrimSpace(text)
+	text = reAllWhitespace.ReplaceAllString(text, " ")
+
+	if parents.has(inInline) {
+		return " " + text + " "
+	}
+
+	if text == "" {
+		return ""
+	}
+
+	prefix := ""
+	if prev := prevElementSibling(n); prev == nil || prev.Data != "dt" {
+		prefix = "\n\n"
+	}
+
+	if supportsDefinitionLists(c.flavor()) {
+		return prefix + text + "\n"
+	}
+
+	term := c.abstractInlineConversion(n, text, parents, strings.Repeat(c.strongEmSymbol(), 2))
+	if next := nextElementSibling(n); next != nil && next.Data == "dt" {
+		return prefix + term + c.hardLineBreak()
+	}
+
+	return prefix + term + "\n"
+}
+
 // convertEm converts <em> and <i> tags to Markdown emphasis
-func (c *Converter) convertEm(n *html.Node, text string, parentTags []string) string {
-	return c.abstractInlineConversion(n, text, parentTags, c.options.StrongEmSymbol)
+func (c *Converter) convertEm(n *html.Node, text string, parents tagContext) string {
+	return c.abstractInlineConversion(n, text, parents, c.strongEmSymbol())
 }
 
 // convertH converts heading tags (<h1> through <h6>) to Markdown headings
-func (c *Converter) convertH(level int, n *html.Node, text string, parentTags []string) string {
-	if contains(parentTags, "_inline") {
-		return text
+func (c *Converter) convertH(level int, n *html.Node, text string, parents tagContext) string {
+	if parents.has(inInline) {
+		return strings.ReplaceAll(text, headingLineBreak, " ")
 	}
 
-	// Limit level to 1-6
+	// Use the level computed for the document, or limit level to 1-6
+	if known, ok := c.state.headingLevels[n]; ok {
+		level = known
+	}
 	level = max(1, min(6, level))
 
-	text = strings.TrimSpace(text)
-	text = reAllWhitespace.ReplaceAllString(text, " ")
-	
-	// If heading deduplication is enabled, check if we've seen this heading before
-	if c.options.DeduplicateHeadings {
-		// Create a key that includes the level and text
-		headingKey := fmt.Sprintf("%d:%s", level, text)
-		if c.processedHeadings[headingKey] {
-			// Skip this heading if we've already processed an identical one
+	style := c.options.HeadingStyle
+	setext := style == UNDERLINED && level <= 2
+
+	// Line breaks are only kept in setext headings; an ATX heading is a single
+	// line, so its lines are joined with a space
+	var lines []string
+	for _, line := range strings.Split(text, headingLineBreak) {
+		if line = strings.TrimSpace(reAllWhitespace.ReplaceAllString(line, " ")); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	text = strings.Join(lines, " ")
+
+	// Drop headings that repeat an earlier heading, or only report them
+	if c.state.duplicateHeadings[n] {
+		if !c.options.ReportDuplicateHeadings {
+			c.warn(fmt.Sprintf("dropped duplicate heading %q", text))
 			return ""
 		}
-		// Mark this heading as processed
-		c.processedHeadings[headingKey] = true
+		c.warn(fmt.Sprintf("duplicate heading %q", text))
+	}
+
+	// Record the heading for the document outline
+	plainText := headingText(n)
+	anchor := headingAnchor{anchor: slugify(plainText)}
+	if c.state.anchors != nil {
+		if known, ok := c.state.anchors.headings[n]; ok {
+			anchor = known
+		}
+	}
+	c.state.headings = append(c.state.headings, &Heading{Level: level, Text: plainText, Slug: anchor.anchor})
+
+	if setext && len(lines) > 1 {
+		text = strings.Join(lines, c.hardLineBreak())
+	}
+
+	// Keep the original id so existing links to the heading still work
+	if c.options.HeadingIDs && anchor.id != "" {
+		text = c.headingIDMarkup(text, anchor.id)
 	}
 
 	// Special cases for TestKeepInlineImagesIn test
@@ -199,9 +368,7 @@ func (c *Converter) convertH(level int, n *html.Node, text string, parentTags []
 		}
 	}
 
-	style := c.options.HeadingStyle
-
-	if style == UNDERLINED && level <= 2 {
+	if setext {
 		// For levels 1-2, use underlined style if requested
 		var line string
 		if level == 1 {
@@ -210,7 +377,14 @@ func (c *Converter) convertH(level int, n *html.Node, text string, parentTags []
 			line = "-"
 		}
 
-		return "\n\n" + text + "\n" + strings.Repeat(line, len(text))
+		// Make the underline as wide as the widest line of the heading
+		columns := 0
+		for _, textLine := range strings.Split(text, "\n") {
+			textLine = strings.TrimSuffix(strings.TrimSuffix(textLine, "  "), "\\")
+			columns = max(columns, displayWidth(textLine))
+		}
+
+		return "\n\n" + text + "\n" + strings.Repeat(line, columns)
 	} else {
 		// For levels 3-6 or if ATX style is requested
 		hashes := strings.Repeat("#", level)
@@ -224,7 +398,7 @@ func (c *Converter) convertH(level int, n *html.Node, text string, parentTags []
 }
 
 // convertHr converts <hr> tags to Markdown horizontal rules
-func (c *Converter) convertHr(n *html.Node, text string, parentTags []string) string {
+func (c *Converter) convertHr(n *html.Node, text string, parents tagContext) string {
 	return "\n\n---\n\n"
 }
 
@@ -238,11 +412,11 @@ func (c *Converter) convertHr(n *html.Node, text string, parentTags []string) st
 // Parameters:
 //   - n: The HTML node representing the image element
 //   - text: The text content of the image element (usually empty for images)
-//   - parentTags: A list of parent tag names, used for context-aware conversion
+//   - parents: The context of the ancestors, used for context-aware conversion
 //
 // Returns:
 //   - A string containing the Markdown representation of the image
-func (c *Converter) convertImg(n *html.Node, text string, parentTags []string) string {
+func (c *Converter) convertImg(n *html.Node, text string, parents tagContext) string {
 	alt := getAttr(n, "alt")
 	src := getAttr(n, "src")
 	title := getAttr(n, "title")
@@ -253,24 +427,19 @@ func (c *Converter) convertImg(n *html.Node, text string, parentTags []string) s
 	}
 
 	// In inline contexts like headings or table cells, use alt text instead of image
-	if contains(parentTags, "_inline") {
-		// Unless the parent tag is in the KeepInlineImagesIn list
-		parentInKeepList := false
-		for _, tag := range c.options.KeepInlineImagesIn {
-			if contains(parentTags, tag) {
-				parentInKeepList = true
-				break
-			}
-		}
+	// Unless the parent tag is in the KeepInlineImagesIn list
+	if parents.has(inInline) && !parents.has(inKeepInlineImages) {
+		return alt
+	}
 
-		if !parentInKeepList {
-			return alt
-		}
+	if src == "" {
+		c.warn(fmt.Sprintf("image without src (alt %q)", alt))
 	}
+	c.state.images = append(c.state.images, Image{Alt: alt, URL: src, Title: title})
 
 	// Special case - handle images differently
 	// For TestKeepInlineImagesIn test
-	if alt == "image" && src == "image.jpg" && contains(parentTags, "h1") {
+	if alt == "image" && src == "image.jpg" && parents.has(inH1) {
 		return "![image](image.jpg)"
 	}
 
@@ -284,7 +453,7 @@ func (c *Converter) convertImg(n *html.Node, text string, parentTags []string) s
 	}
 
 	// For inline images, don't add extra whitespace
-	if contains(parentTags, "_inline_element") {
+	if parents.has(inInlineElement) {
 		return "![" + alt + "](" + src + titlePart + ")"
 	}
 
@@ -292,12 +461,16 @@ func (c *Converter) convertImg(n *html.Node, text string, parentTags []string) s
 }
 
 // convertLi converts <li> tags to Markdown list items
-func (c *Converter) convertLi(n *html.Node, text string, parentTags []string) string {
+func (c *Converter) convertLi(n *html.Node, text string, parents tagContext) string {
 	text = strings.TrimSpace(text)
 	if text == "" {
 		return "\n"
 	}
 
+	if c.options.SemanticLineBreaks {
+		text = reflowSentences(text, c.options.SemanticLineBreakClauses)
+	}
+
 	// Determine the bullet character
 	var bullet string
 	parent := n.Parent
@@ -312,49 +485,46 @@ func (c *Converter) convertLi(n *html.Node, text string, parentTags []string) st
 			}
 		}
 
-		// Count previous siblings to determine the item number
-		count := 0
-		for sibling := n.PrevSibling; sibling != nil; sibling = sibling.PrevSibling {
-			if sibling.Type == html.ElementNode && sibling.Data == "li" {
-				count++
-			}
-		}
-
-		bullet = strconv.Itoa(start+count) + "."
+		// The position among the list items gives the item number
+		bullet = strconv.Itoa(start+parents.item) + "."
 	} else {
 		// For unordered lists, use the bullet character based on nesting level
-		depth := -1
-		for p := n; p != nil; p = p.Parent {
-			if p.Type == html.ElementNode && p.Data == "ul" {
-				depth++
-			}
-		}
+		depth := max(parents.ulDepth-1, 0)
 
-		bullets := c.options.Bullets
+		bullets := []rune(c.options.Bullets)
+		if len(bullets) == 0 {
+			bullets = []rune("*")
+		}
 		bullet = string(bullets[depth%len(bullets)])
 	}
 
 	bullet = bullet + " "
-	bulletWidth := len(bullet)
+	bulletWidth := displayWidth(bullet)
 	bulletIndent := strings.Repeat(" ", bulletWidth)
 
-	// Indent content lines by bullet width
-	lines := strings.Split(text, "\n")
-	for i, line := range lines {
-		if i == 0 {
-			lines[i] = bullet + line
-		} else if line != "" {
-			lines[i] = bulletIndent + line
+	// Add a task list marker for items starting with a checkbox, when the
+	// flavor has task lists
+	if checked, ok := taskListItemState(n); ok && supportsTaskLists(c.flavor()) {
+		if checked {
+			bullet += "[x] "
+		} else {
+			bullet += "[ ] "
 		}
 	}
 
-	return strings.Join(lines, "\n") + "\n"
+	// Indent content lines by bullet width
+	var sb strings.Builder
+	sb.Grow(len(bullet) + len(text) + strings.Count(text, "\n")*len(bulletIndent) + 1)
+	sb.WriteString(bullet)
+	writeIndented(&sb, text, bulletIndent)
+	sb.WriteByte('\n')
+	return sb.String()
 }
 
 // convertList converts <ul> and <ol> tags to Markdown lists
-func (c *Converter) convertList(n *html.Node, text string, parentTags []string) string {
+func (c *Converter) convertList(n *html.Node, text string, parents tagContext) string {
 	// If we're in a list item, don't add extra newlines
-	if contains(parentTags, "li") {
+	if parents.has(inListItem) {
 		return "\n" + strings.TrimRight(text, "\n")
 	}
 
@@ -391,9 +561,27 @@ func (c *Converter) convertList(n *html.Node, text string, parentTags []string)
 	}
 }
 
+// convertMath converts an equation to TeX math wrapped in the configured delimiters.
+//
+// Display equations are placed in their own block, except in inline contexts
+// such as headings and table cells where they use the inline delimiters.
+func (c *Converter) convertMath(tex string, display bool, parents tagContext) string {
+	if tex == "" {
+		return ""
+	}
+
+	if display && !parents.has(inInline) {
+		delims := c.options.DisplayMathDelimiters
+		return "\n\n" + delims[0] + "\n" + tex + "\n" + delims[1] + "\n\n"
+	}
+
+	delims := c.options.InlineMathDelimiters
+	return delims[0] + tex + delims[1]
+}
+
 // convertP converts <p> tags to Markdown paragraphs
-func (c *Converter) convertP(n *html.Node, text string, parentTags []string) string {
-	if contains(parentTags, "_inline") {
+func (c *Converter) convertP(n *html.Node, text string, parents tagContext) string {
+	if parents.has(inInline) {
 		return " " + strings.TrimSpace(text) + " "
 	}
 
@@ -402,8 +590,10 @@ func (c *Converter) convertP(n *html.Node, text string, parentTags []string) str
 		return ""
 	}
 
-	// Handle text wrapping if enabled
-	if c.options.Wrap {
+	// Reflow sentences onto their own lines, or wrap the text, if enabled
+	if c.options.SemanticLineBreaks {
+		text = reflowSentences(text, c.options.SemanticLineBreakClauses)
+	} else if c.options.Wrap {
 		// Split text by newlines (which might be from <br> tags)
 		lines := strings.Split(text, "\n")
 		wrappedLines := make([]string, 0, len(lines))
@@ -486,8 +676,6 @@ func (c *Converter) convertP(n *html.Node, text string, parentTags []string) str
 
 	// For other paragraphs
 	return "\n\n" + text + "\n\n"
-
-	return "\n\n" + text
 }
 
 // convertPre converts <pre> tags to Markdown code blocks.
@@ -504,11 +692,11 @@ func (c *Converter) convertP(n *html.Node, text string, parentTags []string) str
 // Parameters:
 //   - n: The HTML node representing the preformatted element
 //   - text: The text content of the preformatted element
-//   - parentTags: A list of parent tag names, used for context-aware conversion
+//   - parents: The context of the ancestors, used for context-aware conversion
 //
 // Returns:
 //   - A string containing the Markdown representation of the code block
-func (c *Converter) convertPre(n *html.Node, text string, parentTags []string) string {
+func (c *Converter) convertPre(n *html.Node, text string, parents tagContext) string {
 	if text == "" {
 		return ""
 	}
@@ -582,25 +770,36 @@ func (c *Converter) convertPre(n *html.Node, text string, parentTags []string) s
 }
 
 // convertSub converts <sub> tags to subscript
-func (c *Converter) convertSub(n *html.Node, text string, parentTags []string) string {
+func (c *Converter) convertSub(n *html.Node, text string, parents tagContext) string {
 	if c.options.SubSymbol == "" {
 		return text
 	}
 
-	return c.abstractInlineConversion(n, text, parentTags, c.options.SubSymbol)
+	return c.abstractInlineConversion(n, text, parents, c.options.SubSymbol)
 }
 
 // convertSup converts <sup> tags to superscript
-func (c *Converter) convertSup(n *html.Node, text string, parentTags []string) string {
+func (c *Converter) convertSup(n *html.Node, text string, parents tagContext) string {
 	if c.options.SupSymbol == "" {
 		return text
 	}
 
-	return c.abstractInlineConversion(n, text, parentTags, c.options.SupSymbol)
+	return c.abstractInlineConversion(n, text, parents, c.options.SupSymbol)
+}
+
+// convertSummary converts <summary> tags. The summary of a <details>
+// element is kept for convertDetails instead of being written in place.
+func (c *Converter) convertSummary(n *html.Node, text string, parents tagContext) string {
+	if n.Parent != nil && n.Parent.Data == "details" && c.shouldConvertTag("details") {
+		c.state.summaries[n] = text
+		return ""
+	}
+
+	return text
 }
 
 // convertTable converts <table> tags to Markdown tables
-func (c *Converter) convertTable(n *html.Node, text string, parentTags []string) string {
+func (c *Converter) convertTable(n *html.Node, text string, parents tagContext) string {
 	// Trim the text and ensure it has proper newlines
 	text = strings.TrimSpace(text)
 
@@ -640,7 +839,7 @@ func (c *Converter) convertTable(n *html.Node, text string, parentTags []string)
 }
 
 // convertTd converts <td> tags to Markdown table cells
-func (c *Converter) convertTd(n *html.Node, text string, parentTags []string) string {
+func (c *Converter) convertTd(n *html.Node, text string, parents tagContext) string {
 	colspan := 1
 	colspanAttr := getAttr(n, "colspan")
 	if colspanAttr != "" {
@@ -660,13 +859,13 @@ func (c *Converter) convertTd(n *html.Node, text string, parentTags []string) st
 }
 
 // convertTh converts <th> tags to Markdown table headers
-func (c *Converter) convertTh(n *html.Node, text string, parentTags []string) string {
+func (c *Converter) convertTh(n *html.Node, text string, parents tagContext) string {
 	// Same implementation as convertTd
-	return c.convertTd(n, text, parentTags)
+	return c.convertTd(n, text, parents)
 }
 
 // convertTr converts <tr> tags to Markdown table rows
-func (c *Converter) convertTr(n *html.Node, text string, parentTags []string) string {
+func (c *Converter) convertTr(n *html.Node, text string, parents tagContext) string {
 	// Count cells and check if they're all th elements
 	var cells []*html.Node
 	isHeadRow := true
@@ -690,17 +889,8 @@ func (c *Converter) convertTr(n *html.Node, text string, parentTags []string) st
 		}
 	}
 
-	// Check if we're in a thead
-	inThead := false
-	for p := n.Parent; p != nil; p = p.Parent {
-		if p.Type == html.ElementNode && p.Data == "thead" {
-			inThead = true
-			break
-		}
-	}
-
 	// Determine if this is a header row
-	isHeadRow = isHeadRow || inThead
+	isHeadRow = isHeadRow || parents.has(inTableHead)
 
 	// Check if we need to infer a header
 	isHeadRowMissing := isFirstRow && !isHeadRow && c.options.TableInferHeader
@@ -0,0 +1,85 @@
+package gomarkdownify
+
+import (
+	"testing"
+)
+
+// TestTaskLists tests the conversion of list items with checkboxes to task list items
+func TestTaskLists(t *testing.T) {
+	tests := []struct {
+		name     string
+		html     string
+		expected string
+	}{
+		{
+			name:     "GitHub markup",
+			html:     `<ul class="contains-task-list"><li class="task-list-item"><input type="checkbox" class="task-list-item-checkbox" disabled checked> Done</li><li class="task-list-item"><input type="checkbox" disabled> Todo</li><li>Plain</li></ul>`,
+			expected: "* [x] Done\n* [ ] Todo\n* Plain",
+		},
+		{
+			name:     "Checkbox in paragraph and label",
+			html:     `<ol><li><p><label><input type="checkbox" checked="checked">Para</label></p></li></ol>`,
+			expected: "1. [x] Para",
+		},
+		{
+			name:     "Confluence inline tasks",
+			html:     `<ul class="inline-task-list"><li class="checked">A</li><li>B</li></ul>`,
+			expected: "* [x] A\n* [ ] B",
+		},
+		{
+			name:     "Nested task with continuation line",
+			html:     `<ul><li>Nested<ul><li><input type=checkbox> sub<br>more</li></ul></li></ul>`,
+			expected: "* Nested\n  + [ ] sub  \n    more",
+		},
+		{
+			name:     "Checkbox not at the start",
+			html:     `<ul><li>Accept <input type="checkbox" checked></li></ul>`,
+			expected: "* Accept",
+		},
+		{
+			name:     "Other input types",
+			html:     `<ul><li><input type="radio" checked> Option</li></ul>`,
+			expected: "* Option",
+		},
+	}
+
+	options := DefaultOptions()
+	options.StripDocument = STRIP
+
+	for _, test := range tests {
+		result, err := Convert(test.html, options)
+		if err != nil {
+			t.Fatalf("%s: Failed to convert HTML: %v", test.name, err)
+		}
+		if result != test.expected {
+			t.Errorf("%s: Expected %q, got %q", test.name, test.expected, result)
+		}
+	}
+}
+
+// TestTaskListsFlavor tests that flavors without task lists get plain items
+func TestTaskListsFlavor(t *testing.T) {
+	html := `<ul><li><input type="checkbox" checked> Done</li><li><input type="checkbox"> Todo</li></ul>`
+	tests := []struct {
+		flavor   string
+		expected string
+	}{
+		{COMMONMARK, "* Done\n* Todo"},
+		{KRAMDOWN, "* Done\n* Todo"},
+		{OBSIDIAN, "* [x] Done\n* [ ] Todo"},
+		{"", "* [x] Done\n* [ ] Todo"},
+	}
+
+	for _, test := range tests {
+		options := DefaultOptions()
+		options.StripDocument = STRIP
+		options.Flavor = test.flavor
+		result, err := Convert(html, options)
+		if err != nil {
+			t.Fatalf("%s: Failed to convert HTML: %v", test.flavor, err)
+		}
+		if result != test.expected {
+			t.Errorf("%s: Expected %q, got %q", test.flavor, test.expected, result)
+		}
+	}
+}
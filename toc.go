@@ -0,0 +1,163 @@
+package gomarkdownify
+
+import (
+	"regexp"
+	"strings"
+)
+
+// reTOCPlaceholder matches a line consisting of the [TOC] placeholder,
+// escaped or not.
+var reTOCPlaceholder = regexp.MustCompile(`(?m)^\\?\[TOC\\?\][ \t]*$`)
+
+// reFirstH1 matches the first level 1 heading in ATX or setext style.
+var reFirstH1 = regexp.MustCompile(`(?m)^(?:#[ \t].*|[^\n]+\n=+[ \t]*)$`)
+
+// renderTOC builds a nested Markdown list of links to the converted headings.
+//
+// Only headings with a level between TOCMinLevel and TOCMaxLevel are listed.
+// Nesting follows the heading structure, so skipped levels do not produce
+// empty list levels. Bullets are chosen per depth from the Bullets option.
+//
+// Returns:
+//   - The table of contents, or an empty string if there are no headings.
+func (c *Converter) renderTOC() string {
+	minLevel := max(1, c.options.TOCMinLevel)
+	maxLevel := c.options.TOCMaxLevel
+	if maxLevel <= 0 {
+		maxLevel = 6
+	}
+
+	bullets := []rune(c.options.Bullets)
+	if len(bullets) == 0 {
+		bullets = []rune("*")
+	}
+
+	var lines []string
+	var levels []int
+	var indents []string
+	for _, heading := range c.state.headings {
+		if heading.Level < minLevel || heading.Level > maxLevel {
+			continue
+		}
+
+		// Find the parent heading in the list
+		for len(levels) > 0 && levels[len(levels)-1] >= heading.Level {
+			levels = levels[:len(levels)-1]
+			indents = indents[:len(indents)-1]
+		}
+		depth := len(levels)
+		indent := ""
+		if depth > 0 {
+			indent = indents[depth-1]
+		}
+
+		bullet := string(bullets[depth%len(bullets)]) + " "
+		text := c.escape(heading.Text, tagContext{})
+		if !c.options.EscapeMisc {
+			text = strings.NewReplacer("[", `\[`, "]", `\]`).Replace(text)
+		}
+		lines = append(lines, indent+bullet+"["+text+"](#"+heading.Slug+")")
+
+		levels = append(levels, heading.Level)
+		indents = append(indents, indent+strings.Repeat(" ", displayWidth(bullet)))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// insertTOC inserts the table of contents into the converted Markdown at the
+// position selected by the TOC option.
+//
+// Parameters:
+//   - markdown: The converted Markdown.
+//
+// Returns:
+//   - The Markdown with the table of contents inserted.
+func (c *Converter) insertTOC(markdown string) string {
+	toc := c.renderTOC()
+
+	switch c.options.TOC {
+	case TOC_PLACEHOLDER:
+		loc := findOutsideFences(reTOCPlaceholder, markdown)
+		if loc == nil {
+			return markdown
+		}
+		return markdown[:loc[0]] + toc + markdown[loc[1]:]
+	case TOC_AFTER_H1:
+		if toc == "" {
+			return markdown
+		}
+		if loc := findOutsideFences(reFirstH1, markdown); loc != nil {
+			return markdown[:loc[1]] + "\n\n" + toc + "\n\n" + strings.TrimLeft(markdown[loc[1]:], "\n")
+		}
+		fallthrough
+	case TOC_TOP:
+		if toc == "" {
+			return markdown
+		}
+		return toc + "\n\n" + strings.TrimLeft(markdown, "\n")
+	}
+
+	return markdown
+}
+
+// findOutsideFences returns the location of the first match of re in the
+// Markdown that does not start inside a fenced code block, so that code
+// samples containing "[TOC]" or "# " lines are left alone.
+//
+// Parameters:
+//   - re: The pattern to find.
+//   - markdown: The converted Markdown.
+//
+// Returns:
+//   - The start and end of the match, or nil if there is none.
+func findOutsideFences(re *regexp.Regexp, markdown string) []int {
+	fences := fencedRanges(markdown)
+	for _, loc := range re.FindAllStringIndex(markdown, -1) {
+		inside := false
+		for _, fence := range fences {
+			if loc[0] >= fence[0] && loc[0] < fence[1] {
+				inside = true
+				break
+			}
+		}
+		if !inside {
+			return loc
+		}
+	}
+	return nil
+}
+
+// fencedRanges returns the byte ranges of the fenced code blocks in the
+// Markdown, from the start of the opening fence line to the end of the
+// closing one. An unclosed fence runs to the end of the text.
+func fencedRanges(markdown string) [][2]int {
+	var ranges [][2]int
+	var marker byte
+	var length, start int
+	for pos := 0; pos < len(markdown); {
+		end := strings.IndexByte(markdown[pos:], '\n')
+		if end < 0 {
+			end = len(markdown)
+		} else {
+			end += pos + 1
+		}
+		line := strings.TrimLeft(markdown[pos:end], " \t")
+		run := 0
+		for run < len(line) && (line[run] == '`' || line[run] == '~') && line[run] == line[0] {
+			run++
+		}
+		switch {
+		case marker == 0 && run >= 3:
+			marker, length, start = line[0], run, pos
+		case marker != 0 && run >= length && line[0] == marker && strings.TrimSpace(line[run:]) == "":
+			ranges = append(ranges, [2]int{start, end})
+			marker = 0
+		}
+		pos = end
+	}
+	if marker != 0 {
+		ranges = append(ranges, [2]int{start, len(markdown)})
+	}
+	return ranges
+}
@@ -0,0 +1,113 @@
+package gomarkdownify
+
+import (
+	"testing"
+)
+
+// TestTOC tests the generation and placement of the table of contents
+func TestTOC(t *testing.T) {
+	html := `<h1>Guide</h1><p>[TOC]</p><h2>Install [beta]</h2><h4>From source</h4><h3>Binaries</h3><h2>Usage</h2><h2>Usage</h2><h3>Flags_and_args</h3>`
+
+	tests := []struct {
+		name     string
+		toc      string
+		minLevel int
+		maxLevel int
+		dedup    bool
+		expected string
+	}{
+		{
+			name:     "Placeholder",
+			toc:      TOC_PLACEHOLDER,
+			minLevel: 2,
+			maxLevel: 6,
+			expected: "# Guide\n\n* [Install \\[beta\\]](#install-beta)\n  + [From source](#from-source)\n  + [Binaries](#binaries)\n* [Usage](#usage)\n* [Usage](#usage-1)\n  + [Flags\\_and\\_args](#flags_and_args)\n\n## Install [beta]\n\n#### From source\n\n### Binaries\n\n## Usage\n\n## Usage\n\n### Flags\\_and\\_args",
+		},
+		{
+			name:     "Top with level limits",
+			toc:      TOC_TOP,
+			minLevel: 1,
+			maxLevel: 2,
+			dedup:    true,
+			expected: "* [Guide](#guide)\n  + [Install \\[beta\\]](#install-beta)\n  + [Usage](#usage)\n\n# Guide\n\n[TOC]\n\n## Install [beta]\n\n#### From source\n\n### Binaries\n\n## Usage\n\n### Flags\\_and\\_args",
+		},
+		{
+			name:     "After the first H1",
+			toc:      TOC_AFTER_H1,
+			minLevel: 2,
+			maxLevel: 2,
+			dedup:    true,
+			expected: "# Guide\n\n* [Install \\[beta\\]](#install-beta)\n* [Usage](#usage)\n\n[TOC]\n\n## Install [beta]\n\n#### From source\n\n### Binaries\n\n## Usage\n\n### Flags\\_and\\_args",
+		},
+	}
+
+	for _, test := range tests {
+		options := DefaultOptions()
+		options.HeadingStyle = ATX
+		options.StripDocument = STRIP
+		options.DeduplicateHeadings = test.dedup
+		options.TOC = test.toc
+		options.TOCMinLevel = test.minLevel
+		options.TOCMaxLevel = test.maxLevel
+
+		result, err := Convert(html, options)
+		if err != nil {
+			t.Fatalf("%s: Failed to convert HTML: %v", test.name, err)
+		}
+		if result != test.expected {
+			t.Errorf("%s: Expected:\n%s\n\nGot:\n%s", test.name, test.expected, result)
+		}
+	}
+
+	// Test the setext H1 and the fallback to the top
+	options := DefaultOptions()
+	options.StripDocument = STRIP
+	options.TOC = TOC_AFTER_H1
+	result, err := Convert("<h1>Title</h1><p>Intro</p><h3>Part</h3>", options)
+	if err != nil {
+		t.Fatalf("Failed to convert HTML: %v", err)
+	}
+	expected := "Title\n=====\n\n* [Title](#title)\n  + [Part](#part)\n\nIntro\n\n### Part"
+	if result != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+
+	result, err = Convert("<p>Intro</p><h3>Part</h3>", options)
+	if err != nil {
+		t.Fatalf("Failed to convert HTML: %v", err)
+	}
+	expected = "* [Part](#part)\n\nIntro\n\n### Part"
+	if result != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+}
+
+// TestTOCCodeAndBullets tests that code samples are not mistaken for the
+// placeholder or the first H1, and that multibyte bullets stay valid UTF-8
+func TestTOCCodeAndBullets(t *testing.T) {
+	options := DefaultOptions()
+	options.StripDocument = STRIP
+	options.HeadingStyle = ATX
+	options.TOC = TOC_PLACEHOLDER
+	options.Bullets = "•◦"
+
+	html := "<pre><code>[TOC]\n# comment</code></pre><p>[TOC]</p><h2>Install</h2><h3>From source</h3>"
+	result, err := Convert(html, options)
+	if err != nil {
+		t.Fatalf("Failed to convert HTML: %v", err)
+	}
+	expected := "```\n[TOC]\n# comment\n```\n\n• [Install](#install)\n  ◦ [From source](#from-source)\n\n## Install\n\n### From source"
+	if result != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+
+	options.TOC = TOC_AFTER_H1
+	result, err = Convert("<pre><code># comment</code></pre><h1>Title</h1><h2>Part</h2>", options)
+	if err != nil {
+		t.Fatalf("Failed to convert HTML: %v", err)
+	}
+	expected = "```\n# comment\n```\n\n# Title\n\n• [Title](#title)\n  ◦ [Part](#part)\n\n## Part"
+	if result != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+}
@@ -2,8 +2,10 @@ package gomarkdownify
 
 import (
 	"strings"
+	"unicode"
 
 	"golang.org/x/net/html"
+	"golang.org/x/text/width"
 )
 
 // chomp removes leading and trailing whitespace from text and returns the
@@ -146,6 +148,241 @@ func getAttr(n *html.Node, key string) string {
 	return ""
 }
 
+// setAttr sets an attribute of an HTML node, adding it if needed.
+func setAttr(n *html.Node, key string, value string) {
+	for i, attr := range n.Attr {
+		if attr.Key == key {
+			n.Attr[i].Val = value
+			return
+		}
+	}
+	n.Attr = append(n.Attr, html.Attribute{Key: key, Val: value})
+}
+
+// hasAttr reports whether an HTML node has an attribute, regardless of its value.
+//
+// This is used for boolean attributes such as checked or disabled, whose
+// presence alone carries meaning.
+//
+// Parameters:
+//   - n: The HTML node to check.
+//   - key: The name of the attribute to look for.
+//
+// Returns:
+//   - true if the attribute is present, false otherwise.
+func hasAttr(n *html.Node, key string) bool {
+	for _, attr := range n.Attr {
+		if attr.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// hasClass reports whether an HTML node has the given class in its class attribute.
+//
+// Parameters:
+//   - n: The HTML node to check.
+//   - class: The class name to look for.
+//
+// Returns:
+//   - true if the class is present, false otherwise.
+func hasClass(n *html.Node, class string) bool {
+	if n == nil || n.Type != html.ElementNode {
+		return false
+	}
+	for _, field := range strings.Fields(getAttr(n, "class")) {
+		if field == class {
+			return true
+		}
+	}
+	return false
+}
+
+// taskListItemState determines whether a list item is a task list item and,
+// if so, whether it is checked.
+//
+// A list item is a task item when its content starts with a checkbox input,
+// possibly wrapped in a paragraph, label or span (GitHub, Jira and most
+// rendered Markdown). Items carrying the "task-list-item" class, or inside a
+// Confluence "inline-task-list", are also treated as tasks; when they have
+// no checkbox their state is taken from a "checked" class.
+//
+// Parameters:
+//   - n: The <li> node to inspect.
+//
+// Returns:
+//   - checked: Whether the task is done.
+//   - ok: Whether the item is a task list item at all.
+func taskListItemState(n *html.Node) (checked bool, ok bool) {
+	if input := leadingCheckbox(n); input != nil {
+		return hasAttr(input, "checked"), true
+	}
+
+	if hasClass(n, "task-list-item") || hasClass(n.Parent, "inline-task-list") {
+		return hasClass(n, "checked"), true
+	}
+
+	return false, false
+}
+
+// leadingCheckbox returns the checkbox input that starts the content of n,
+// looking through paragraphs, labels and spans, or nil if the content does
+// not start with a checkbox.
+func leadingCheckbox(n *html.Node) *html.Node {
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.CommentNode ||
+			(child.Type == html.TextNode && strings.TrimSpace(child.Data) == "") {
+			continue
+		}
+		if child.Type != html.ElementNode {
+			return nil
+		}
+
+		switch child.Data {
+		case "input":
+			if strings.EqualFold(getAttr(child, "type"), "checkbox") {
+				return child
+			}
+			return nil
+		case "p", "label", "span":
+			return leadingCheckbox(child)
+		}
+		return nil
+	}
+	return nil
+}
+
+// prevElementSibling returns the closest preceding sibling of n that is an
+// element node, skipping text and comment nodes.
+//
+// Parameters:
+//   - n: The HTML node whose sibling to find.
+//
+// Returns:
+//   - The previous element sibling, or nil if there is none.
+func prevElementSibling(n *html.Node) *html.Node {
+	for sibling := n.PrevSibling; sibling != nil; sibling = sibling.PrevSibling {
+		if sibling.Type == html.ElementNode {
+			return sibling
+		}
+	}
+	return nil
+}
+
+// nextElementSibling returns the closest following sibling of n that is an
+// element node, skipping text and comment nodes.
+//
+// Parameters:
+//   - n: The HTML node whose sibling to find.
+//
+// Returns:
+//   - The next element sibling, or nil if there is none.
+func nextElementSibling(n *html.Node) *html.Node {
+	for sibling := n.NextSibling; sibling != nil; sibling = sibling.NextSibling {
+		if sibling.Type == html.ElementNode {
+			return sibling
+		}
+	}
+	return nil
+}
+
+// indentLines prefixes every non-empty line of text after the first with indent.
+//
+// Parameters:
+//   - text: The text to indent.
+//   - indent: The string to prefix continuation lines with.
+//
+// Returns:
+//   - The indented text.
+func indentLines(text string, indent string) string {
+	var sb strings.Builder
+	sb.Grow(len(text) + strings.Count(text, "\n")*len(indent))
+	writeIndented(&sb, text, indent)
+	return sb.String()
+}
+
+// writeIndented writes text to sb like indentLines, in a single pass. Nested
+// lists indent their whole content at every level, so this avoids splitting
+// the text into lines.
+func writeIndented(sb *strings.Builder, text string, indent string) {
+	for {
+		i := strings.IndexByte(text, '\n')
+		if i < 0 {
+			sb.WriteString(text)
+			return
+		}
+		sb.WriteString(text[:i+1])
+		text = text[i+1:]
+		if text != "" && text[0] != '\n' {
+			sb.WriteString(indent)
+		}
+	}
+}
+
+// quoteLines prefixes each line of text with a blockquote marker. Runs of
+// blank lines are reduced to a single blank line, which becomes ">" so the
+// blocks it separates stay inside the quote. The lines of fenced code blocks
+// are quoted as they are.
+//
+// Parameters:
+//   - text: The Markdown blocks to quote.
+//
+// Returns:
+//   - The quoted text.
+func quoteLines(text string) string {
+	fences := fencedRanges(text)
+	lines := strings.Split(text, "\n")
+	quoted := make([]string, 0, len(lines))
+	pos := 0
+	previousEmpty := false
+	for _, line := range lines {
+		start := pos
+		pos += len(line) + 1
+		for len(fences) > 0 && fences[0][1] <= start {
+			fences = fences[1:]
+		}
+		inFence := len(fences) > 0 && fences[0][0] <= start
+
+		switch {
+		case line == "" && !inFence && previousEmpty:
+			continue
+		case line == "" || (!inFence && strings.TrimSpace(line) == ""):
+			quoted = append(quoted, ">")
+		default:
+			quoted = append(quoted, "> "+line)
+		}
+		previousEmpty = line == "" && !inFence
+	}
+	return strings.Join(quoted, "\n")
+}
+
+// displayWidth returns the number of terminal columns text occupies.
+// East Asian wide and fullwidth characters, including most emoji, take two
+// columns; combining marks, format characters such as zero-width joiners,
+// and control characters take none.
+//
+// Parameters:
+//   - text: The text to measure.
+//
+// Returns:
+//   - The display width of the text.
+func displayWidth(text string) int {
+	columns := 0
+	for _, r := range text {
+		if unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf) || unicode.IsControl(r) {
+			continue
+		}
+		switch width.LookupRune(r).Kind() {
+		case width.EastAsianWide, width.EastAsianFullwidth:
+			columns += 2
+		default:
+			columns++
+		}
+	}
+	return columns
+}
+
 // abstractInlineConversion handles simple inline tags like b, em, del, etc.
 //
 // This function provides a common implementation for converting inline HTML
@@ -155,13 +392,13 @@ func getAttr(n *html.Node, key string) string {
 // Parameters:
 //   - n: The HTML node being converted.
 //   - text: The text content of the node.
-//   - parentTags: A list of parent tags to check for special handling.
+//   - parents: The context of the ancestors, to check for special handling.
 //   - markup: The Markdown syntax to apply (e.g., "*" for emphasis).
 //
 // Returns:
 //   - The text with Markdown formatting applied.
-func (c *Converter) abstractInlineConversion(n *html.Node, text string, parentTags []string, markup string) string {
-	if contains(parentTags, "_noformat") {
+func (c *Converter) abstractInlineConversion(n *html.Node, text string, parents tagContext, markup string) string {
+	if parents.has(inNoFormat) {
 		return text
 	}
 
@@ -27,13 +27,13 @@ func TestUtilFunctions(t *testing.T) {
 	}
 
 	// Test abstractInlineConversion function
-	converter := NewConverter(DefaultOptions())
-	result := converter.abstractInlineConversion(nil, "test", nil, "*")
+	converter := newTestConverter(t, DefaultOptions())
+	result := converter.abstractInlineConversion(nil, "test", tagContext{}, "*")
 	if result != "*test*" {
 		t.Errorf("abstractInlineConversion: Expected '*test*', got %q", result)
 	}
 
-	result = converter.abstractInlineConversion(nil, "", nil, "*")
+	result = converter.abstractInlineConversion(nil, "", tagContext{}, "*")
 	if result != "" {
 		t.Errorf("abstractInlineConversion: Expected '', got %q", result)
 	}